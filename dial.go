@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// volumeDial changes the volume of a device by rotation and toggles mute by pressing or tapping
+type volumeDial struct {
+	handler *musicCastHandler
+}
+
+func (a volumeDial) HandleDialRotateEvent(sender sdplugin.Sender, event sdplugin.DialRotateEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.rotate(sender, event.Context, settings, event.Payload.Ticks)
+}
+
+func (a volumeDial) HandleDialDownEvent(sender sdplugin.Sender, event sdplugin.DialEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.toggleMute(sender, event.Context, settings)
+}
+
+func (a volumeDial) HandleTouchTapEvent(sender sdplugin.Sender, event sdplugin.TouchTapEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.toggleMute(sender, event.Context, settings)
+}
+
+func (a volumeDial) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	return a.show(sender, context, actionContext.settings, state.status)
+}
+
+// rotate changes the volume by the configured step per tick.
+// The step of the device is used if no step is configured.
+// The change is relative, so fast turns whose events are handled concurrently do not lose ticks.
+func (a volumeDial) rotate(sender sdplugin.Sender, sdContext string, settings Settings, ticks int) error {
+	if ticks == 0 {
+		return nil
+	}
+
+	step := settings.Step
+	if step == 0 {
+		step = 1
+		if rangeStep := a.volumeRangeStep(settings); rangeStep != nil && rangeStep.Step > 0 {
+			step = int(rangeStep.Step)
+		}
+	}
+
+	direction := musiccast.DirectionUp
+	if ticks < 0 {
+		direction = musiccast.DirectionDown
+		ticks = -ticks
+	}
+
+	// the device clamps the volume to its range
+	device := a.handler.device(settings)
+	err := device.SetVolumeStep(context.Background(), direction, ticks*step)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return a.show(sender, sdContext, settings, status)
+}
+
+// toggleMute toggles mute when the dial is pressed or the touch strip is tapped
+func (a volumeDial) toggleMute(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	err = device.SetMute(context.Background(), !status.Mute)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err = device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return a.show(sender, sdContext, settings, status)
+}
+
+// show renders the input name and a volume bar on the touch strip
+func (a volumeDial) show(sender sdplugin.Sender, sdContext string, settings Settings, status *musiccast.Status) error {
+	value := strconv.Itoa(status.Volume)
+	push := "Mute"
+	if status.Mute {
+		value = "Muted"
+		push = "Unmute"
+	}
+
+	percent := 0
+	if limit := maxVolume(status, a.volumeRangeStep(settings)); limit > 0 {
+		percent = clamp(status.Volume*100/limit, 0, 100)
+	}
+
+	err := sender.SetFeedback(sdContext, sdplugin.BarFeedback{
+		Title:     a.handler.inputName(settings.deviceKey().ip, status.Input),
+		Value:     value,
+		Indicator: &sdplugin.Bar{Value: percent},
+	})
+	if err != nil {
+		return err
+	}
+	return sender.SetTriggerDescription(sdContext, sdplugin.TriggerDescription{Push: push, Touch: push})
+}
+
+// volumeRangeStep returns the volume range of the configured zone, nil if it is unknown
+func (a volumeDial) volumeRangeStep(settings Settings) *musiccast.RangeStep {
+	features, err := a.handler.features(settings.deviceKey().ip)
+	if err != nil {
+		return nil
+	}
+	zone := features.ZoneFeatures(settings.deviceKey().zone)
+	if zone == nil {
+		return nil
+	}
+	return zone.RangeStepFor("volume")
+}
+
+// maxVolume of the zone from its status or its volume range.
+// Some devices do not report max_volume, 0 if neither is known.
+func maxVolume(status *musiccast.Status, rangeStep *musiccast.RangeStep) int {
+	if status.MaxVolume > 0 {
+		return status.MaxVolume
+	}
+	if rangeStep != nil {
+		return int(rangeStep.Max)
+	}
+	return 0
+}
+
+// inputName returns the display name of the input, the input id if it is unknown
+func (m *musicCastHandler) inputName(ip string, input string) string {
+	m.nameTextMapMutex.Lock()
+	nameText, ok := m.nameTextMap[ip]
+	m.nameTextMapMutex.Unlock()
+
+	if !ok {
+		var err error
+		nameText, err = musiccast.NewClient(ip, m.httpClient).GetNameText(context.Background(), "")
+		if err != nil {
+			log.Printf("Could not fetch input names: %v\n", err)
+			return input
+		}
+
+		m.nameTextMapMutex.Lock()
+		m.nameTextMap[ip] = nameText
+		m.nameTextMapMutex.Unlock()
+	}
+
+	for _, name := range nameText.InputList {
+		if name.ID == input {
+			return name.Text
+		}
+	}
+	return input
+}
+
+// nameTextChanged drops the cached input names of the device at ip
+func (m *musicCastHandler) nameTextChanged(ip string) {
+	m.nameTextMapMutex.Lock()
+	delete(m.nameTextMap, ip)
+	m.nameTextMapMutex.Unlock()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// discoveryTimeout waiting for devices to answer the SSDP search
+const discoveryTimeout = 2 * time.Second
+
+// discoverDevices searches the network for MusicCast devices and remembers them.
+// Changes are saved in the global settings and keys follow their devices.
+// Returns all devices known so far.
+func (m *musicCastHandler) discoverDevices(sender sdplugin.Sender) []musiccast.DiscoveredDevice {
+	// one search at a time is enough, concurrent callers get the result of the next one
+	m.discoveryMutex.Lock()
+	defer m.discoveryMutex.Unlock()
+
+	discoverer := musiccast.Discoverer{
+		Timeout:    discoveryTimeout,
+		HTTPClient: m.httpClient,
+	}
+	devices, err := discoverer.Discover(context.Background())
+	if err != nil {
+		log.Printf("Device discovery failed: %v\n", err)
+		return m.knownDevices()
+	}
+	log.Printf("Discovered %v MusicCast devices\n", len(devices))
+
+	changed := false
+	m.deviceMapMutex.Lock()
+	for _, device := range devices {
+		if m.deviceMap[device.UUID] != device {
+			m.deviceMap[device.UUID] = device
+			changed = true
+		}
+	}
+	m.deviceMapMutex.Unlock()
+
+	if changed {
+		m.saveDevices(sender)
+		m.resolveContexts(sender)
+	}
+	return m.knownDevices()
+}
+
+// knownDevices returns all discovered devices sorted by name
+func (m *musicCastHandler) knownDevices() []musiccast.DiscoveredDevice {
+	m.deviceMapMutex.Lock()
+	defer m.deviceMapMutex.Unlock()
+
+	devices := make([]musiccast.DiscoveredDevice, 0, len(m.deviceMap))
+	for _, device := range m.deviceMap {
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].FriendlyName < devices[j].FriendlyName
+	})
+	return devices
+}
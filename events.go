@@ -0,0 +1,91 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+)
+
+// eventRenewInterval in which devices sending events are polled to renew their subscription
+const eventRenewInterval = musiccast.EventSubscriptionTimeout / 2
+
+// zones which can be part of an event
+var eventZones = []string{musiccast.ZoneMain, musiccast.Zone2, musiccast.Zone3, musiccast.Zone4}
+
+// eventWorker receives events of all subscribed devices and lets their pollers update immediately
+func (m *musicCastHandler) eventWorker() {
+	log.Printf("Listening for events on port %v\n", m.events.Port())
+	for true {
+		ip, event, err := m.events.ReadEvent()
+		if err != nil {
+			log.Printf("Stopping event worker: %v\n", err)
+			return
+		}
+
+		m.lastEventMapMutex.Lock()
+		m.lastEventMap[ip] = time.Now()
+		m.lastEventMapMutex.Unlock()
+
+		if event.NetUSB != nil && event.NetUSB.PresetInfoUpdated {
+			m.presetInfoChanged(ip)
+		}
+		if event.System != nil && event.System.NameTextUpdated {
+			m.nameTextChanged(ip)
+		}
+
+		// net/usb, cd, the tuner, MusicCast Link and system functions are shared by all zones
+		if event.NetUSB != nil && (event.NetUSB.PlayInfoUpdated || event.NetUSB.PresetInfoUpdated) ||
+			event.CD != nil && event.CD.PlayInfoUpdated ||
+			event.Tuner != nil && event.Tuner.PlayInfoUpdated ||
+			event.Dist != nil && event.Dist.DistInfoUpdated ||
+			event.System != nil && (event.System.FuncStatusUpdated || event.System.NameTextUpdated) {
+			m.refreshDeviceZones(ip)
+			continue
+		}
+
+		for _, zone := range eventZones {
+			if event.Zone(zone) != nil {
+				m.refreshDevice(deviceKey{ip: ip, zone: zone})
+			}
+		}
+	}
+}
+
+// receivesEvents reports if the device sent an event since it was subscribed.
+// The device is polled until it does and after the subscription was lost.
+func (m *musicCastHandler) receivesEvents(ip string) bool {
+	m.lastEventMapMutex.Lock()
+	defer m.lastEventMapMutex.Unlock()
+	_, ok := m.lastEventMap[ip]
+	return ok
+}
+
+// checkSubscription clears the event mark of the device if renewing the subscription failed
+// or the status changed since the last poll without an event reporting it
+func (m *musicCastHandler) checkSubscription(ip string, previous *musiccast.Status, current *musiccast.Status, lastPoll time.Time, err error) {
+	m.lastEventMapMutex.Lock()
+	defer m.lastEventMapMutex.Unlock()
+
+	lastEvent, ok := m.lastEventMap[ip]
+	if !ok {
+		return
+	}
+	if err != nil {
+		log.Printf("Polling %v until it sends events again, renewing the subscription failed: %v\n", ip, err)
+		delete(m.lastEventMap, ip)
+		return
+	}
+	if previous != nil && current != nil && statusChanged(previous, current) && lastEvent.Before(lastPoll) {
+		log.Printf("Polling %v until it sends events again, a status change was not reported\n", ip)
+		delete(m.lastEventMap, ip)
+	}
+}
+
+// statusChanged reports if the status of a zone changed.
+// The sleep timer is ignored as it counts down without events.
+func statusChanged(previous *musiccast.Status, current *musiccast.Status) bool {
+	a, b := *previous, *current
+	a.Sleep, b.Sleep = 0, 0
+	return a != b
+}
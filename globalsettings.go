@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// globalSettings are shared by all keys and saved by streamdeck
+type globalSettings struct {
+	// Devices known from previous discoveries, so they are available before
+	// the first discovery finished and when discovery fails
+	Devices []musiccast.DiscoveredDevice `json:"Devices,omitempty"`
+}
+
+// start requests the global settings, searches for devices and starts the sleep timer countdown
+// once the first sender is available.
+// Devices found before the global settings arrived are saved together with them.
+func (m *musicCastHandler) start(sender sdplugin.Sender) {
+	m.startOnce.Do(func() {
+		err := sender.GetGlobalSettings()
+		if err != nil {
+			log.Printf("Could not request global settings: %v\n", err)
+		}
+		go m.discoverDevices(sender)
+		go m.sleepWorker(sender)
+	})
+}
+
+func (m *musicCastHandler) HandleDidReceiveGlobalSettingsEvent(sender sdplugin.Sender, event sdplugin.DidReceiveGlobalSettingsEventMessage) error {
+	var settings globalSettings
+	if len(event.Payload.Settings) > 0 {
+		err := json.Unmarshal(event.Payload.Settings, &settings)
+		if err != nil {
+			return err
+		}
+	}
+
+	// devices found by a discovery in the meantime are more recent
+	m.deviceMapMutex.Lock()
+	changed := false
+	for _, device := range settings.Devices {
+		if known, ok := m.deviceMap[device.UUID]; !ok {
+			m.deviceMap[device.UUID] = device
+		} else if known != device {
+			changed = true
+		}
+	}
+	if len(m.deviceMap) != len(settings.Devices) {
+		changed = true
+	}
+	m.globalSettingsLoaded = true
+	m.deviceMapMutex.Unlock()
+
+	log.Printf("Loaded %v devices from global settings\n", len(settings.Devices))
+	if changed {
+		m.saveDevices(sender)
+	}
+	m.resolveContexts(sender)
+	return nil
+}
+
+// saveDevices stores all known devices in the global settings.
+// Nothing is saved before the global settings were received, they would be overwritten.
+func (m *musicCastHandler) saveDevices(sender sdplugin.Sender) {
+	m.deviceMapMutex.Lock()
+	loaded := m.globalSettingsLoaded
+	m.deviceMapMutex.Unlock()
+	if !loaded {
+		return
+	}
+
+	err := sender.SetGlobalSettings(globalSettings{Devices: m.knownDevices()})
+	if err != nil {
+		log.Printf("Could not save devices: %v\n", err)
+	}
+}
+
+// resolveSettings loads settings and resolves the address of their device from the global settings
+func (m *musicCastHandler) resolveSettings(payload []byte) (Settings, error) {
+	settings, err := loadSettings(payload)
+	if err != nil {
+		return Settings{}, err
+	}
+	return m.resolveDevice(settings), nil
+}
+
+// resolveDevice sets the host of the configured device. Devices not known yet have no host.
+func (m *musicCastHandler) resolveDevice(settings Settings) Settings {
+	settings.host = settings.IP
+	if settings.Device != "" {
+		m.deviceMapMutex.Lock()
+		settings.host = m.deviceMap[settings.Device].Host
+		m.deviceMapMutex.Unlock()
+	}
+	return settings
+}
+
+// hosts returns the addresses of devices given by UUID or IP. Unknown UUIDs are left out.
+func (m *musicCastHandler) hosts(devices []string) []string {
+	m.deviceMapMutex.Lock()
+	defer m.deviceMapMutex.Unlock()
+
+	hosts := make([]string, 0, len(devices))
+	for _, device := range devices {
+		if net.ParseIP(device) != nil {
+			hosts = append(hosts, device)
+		} else if known, ok := m.deviceMap[device]; ok {
+			hosts = append(hosts, known.Host)
+		}
+	}
+	return hosts
+}
+
+// adoptDevices replaces IPs of discovered devices in settings by their UUID,
+// so the address is only stored in the global settings. Reports if settings changed.
+func (m *musicCastHandler) adoptDevices(settings Settings) (Settings, bool) {
+	m.deviceMapMutex.Lock()
+	uuids := make(map[string]string, len(m.deviceMap))
+	for _, device := range m.deviceMap {
+		uuids[device.Host] = device.UUID
+	}
+	m.deviceMapMutex.Unlock()
+
+	changed := false
+	if uuid, ok := uuids[settings.IP]; ok && settings.Device == "" {
+		settings.Device = uuid
+		settings.IP = ""
+		changed = true
+	}
+	if len(settings.Clients) > 0 {
+		clients := make([]string, len(settings.Clients))
+		for i, client := range settings.Clients {
+			clients[i] = client
+			if uuid, ok := uuids[client]; ok {
+				clients[i] = uuid
+				changed = true
+			}
+		}
+		settings.Clients = clients
+	}
+	return m.resolveDevice(settings), changed
+}
+
+// resolveContexts updates all contexts after the known devices changed.
+// Contexts follow their device to its new address, IPs of discovered devices are replaced by their UUID.
+func (m *musicCastHandler) resolveContexts(sender sdplugin.Sender) {
+	m.contextMapMutex.Lock()
+	contextMap := make(map[string]actionContext, len(m.contextMap))
+	for context, actionContext := range m.contextMap {
+		contextMap[context] = actionContext
+	}
+	m.contextMapMutex.Unlock()
+
+	for context, actionContext := range contextMap {
+		settings, changed := m.adoptDevices(actionContext.settings)
+		if changed {
+			err := m.updateSettings(sender, context, settings)
+			if err != nil {
+				log.Printf("Failed to save device: %v\n", err)
+			}
+		} else if settings.deviceKey() != actionContext.settings.deviceKey() {
+			m.moveContext(sender, context, settings)
+		}
+	}
+}
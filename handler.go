@@ -1,175 +1,337 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
 	"log"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
 	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
 )
 
+// UUIDs of all actions defined in manifest.json
+const (
+	powerAction        = "de.louischrist.musiccast.power"
+	volumeUpAction     = "de.louischrist.musiccast.volumeup"
+	volumeDownAction   = "de.louischrist.musiccast.volumedown"
+	volumeSetAction    = "de.louischrist.musiccast.volume"
+	muteAction         = "de.louischrist.musiccast.mute"
+	inputAction        = "de.louischrist.musiccast.input"
+	nowPlayingAction   = "de.louischrist.musiccast.nowplaying"
+	playPauseAction    = "de.louischrist.musiccast.playpause"
+	stopAction         = "de.louischrist.musiccast.stop"
+	nextAction         = "de.louischrist.musiccast.next"
+	previousAction     = "de.louischrist.musiccast.previous"
+	shuffleAction      = "de.louischrist.musiccast.shuffle"
+	repeatAction       = "de.louischrist.musiccast.repeat"
+	presetAction       = "de.louischrist.musiccast.preset"
+	sceneAction        = "de.louischrist.musiccast.scene"
+	tunerBandAction    = "de.louischrist.musiccast.tunerband"
+	tunerFreqAction    = "de.louischrist.musiccast.tunerfreq"
+	tunerPresetAction  = "de.louischrist.musiccast.tunerpreset"
+	soundProgramAction = "de.louischrist.musiccast.soundprogram"
+	soundToggleAction  = "de.louischrist.musiccast.soundtoggle"
+	toneControlAction  = "de.louischrist.musiccast.tonecontrol"
+	sleepAction        = "de.louischrist.musiccast.sleep"
+	linkAction         = "de.louischrist.musiccast.link"
+	partyAction        = "de.louischrist.musiccast.party"
+	dialAction         = "de.louischrist.musiccast.dial"
+)
+
+// actionContext contains the action and settings of a visible streamdeck context
+type actionContext struct {
+	action   string
+	settings Settings
+}
+
 type musicCastHandler struct {
 	contextMapMutex *sync.Mutex
-	contextMap      map[string]Settings
+	contextMap      map[string]actionContext
+
+	// pollerMap is the device registry with one poller per device zone
+	pollerMapMutex *sync.Mutex
+	pollerMap      map[deviceKey]*devicePoller
+
+	httpClient *http.Client
+
+	// events is nil if no event listener could be opened
+	events *musiccast.EventListener
+
+	// lastEventMap marks devices sending events with the time of their last event.
+	// Devices without a mark are polled.
+	lastEventMapMutex *sync.Mutex
+	lastEventMap      map[string]time.Time
+
+	// deviceMap contains all discovered devices by UUID
+	deviceMapMutex *sync.Mutex
+	deviceMap      map[string]musiccast.DiscoveredDevice
+	discoveryMutex *sync.Mutex
+	// globalSettingsLoaded is set once the devices of the global settings were merged into deviceMap
+	globalSettingsLoaded bool
+
+	// startOnce loads the global settings and starts discovery and the sleep countdown with the first event
+	startOnce *sync.Once
+
+	// featureMap caches the features of each device by IP
+	featureMapMutex *sync.Mutex
+	featureMap      map[string]*musiccast.Features
 
-	cancelMapMutex *sync.Mutex
-	cancelMap      map[string]context.CancelFunc
+	// presetMap caches the net/usb presets of each device by IP
+	presetMapMutex *sync.Mutex
+	presetMap      map[string]*musiccast.PresetInfo
 
-	client *http.Client
+	// nameTextMap caches the display names of each device by IP
+	nameTextMapMutex *sync.Mutex
+	nameTextMap      map[string]*musiccast.NameText
+
+	// propertyInspectorMap contains the contexts with a visible property inspector
+	propertyInspectorMapMutex *sync.Mutex
+	propertyInspectorMap      map[string]bool
+
+	// keyDownMap contains when keys waiting for their keyUp event were pressed
+	keyDownMapMutex *sync.Mutex
+	keyDownMap      map[string]time.Time
+
+	// sleepMap contains the running sleep timers of each device zone
+	sleepMapMutex *sync.Mutex
+	sleepMap      map[deviceKey]sleepTimer
+
+	// albumArtMap contains the album art URL shown by each now playing context
+	albumArtMapMutex *sync.Mutex
+	albumArtMap      map[string]string
+
+	// actionMap contains the handler of each action by UUID, it is not modified after initialization.
+	// Events an action does not handle itself are handled by musicCastHandler.
+	actionMap map[string]sdplugin.Handler
 }
 
-//newMusicCastHandler initializes a new musicCastHandler
-func newMusicCastHandler() *musicCastHandler {
-	return &musicCastHandler{
-		contextMapMutex: &sync.Mutex{},
-		contextMap:      make(map[string]Settings),
-		cancelMapMutex:  &sync.Mutex{},
-		cancelMap:       make(map[string]context.CancelFunc),
-		client: &http.Client{
-			Timeout: 3 * time.Second,
-		},
-	}
+// renderer is implemented by actions showing the state of their device
+type renderer interface {
+	render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error
 }
 
-func (m *musicCastHandler) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
-	settings, err := loadSettings(event.Payload.Settings)
+//newMusicCastHandler initializes a new musicCastHandler
+func newMusicCastHandler() *musicCastHandler {
+	events, err := musiccast.ListenEvents(":0")
 	if err != nil {
-		return err
+		log.Printf("Could not listen for events, falling back to polling: %v\n", err)
+		events = nil
 	}
 
-	err = m.musicCastPowerToggle(settings.IP)
-	if err != nil {
-		sender.ShowAlert(event.Context)
-		return err
+	m := &musicCastHandler{
+		contextMapMutex: &sync.Mutex{},
+		contextMap:      make(map[string]actionContext),
+		pollerMapMutex:  &sync.Mutex{},
+		pollerMap:       make(map[deviceKey]*devicePoller),
+		httpClient: &http.Client{
+			Timeout: 3 * time.Second,
+		},
+		events:                    events,
+		lastEventMapMutex:         &sync.Mutex{},
+		lastEventMap:              make(map[string]time.Time),
+		deviceMapMutex:            &sync.Mutex{},
+		deviceMap:                 make(map[string]musiccast.DiscoveredDevice),
+		discoveryMutex:            &sync.Mutex{},
+		startOnce:                 &sync.Once{},
+		featureMapMutex:           &sync.Mutex{},
+		featureMap:                make(map[string]*musiccast.Features),
+		presetMapMutex:            &sync.Mutex{},
+		presetMap:                 make(map[string]*musiccast.PresetInfo),
+		nameTextMapMutex:          &sync.Mutex{},
+		nameTextMap:               make(map[string]*musiccast.NameText),
+		propertyInspectorMapMutex: &sync.Mutex{},
+		propertyInspectorMap:      make(map[string]bool),
+		keyDownMapMutex:           &sync.Mutex{},
+		keyDownMap:                make(map[string]time.Time),
+		sleepMapMutex:             &sync.Mutex{},
+		sleepMap:                  make(map[deviceKey]sleepTimer),
+		albumArtMapMutex:          &sync.Mutex{},
+		albumArtMap:               make(map[string]string),
 	}
 
-	on, err := m.isMusicCastPowerOn(settings.IP)
-	if err != nil {
-		return err
+	m.actionMap = map[string]sdplugin.Handler{
+		powerAction:        powerKey{m},
+		volumeUpAction:     volumeKey{m, volumeUpAction},
+		volumeDownAction:   volumeKey{m, volumeDownAction},
+		volumeSetAction:    volumeKey{m, volumeSetAction},
+		muteAction:         muteKey{m},
+		inputAction:        inputKey{m},
+		nowPlayingAction:   nowPlayingKey{m},
+		playPauseAction:    transportKey{m, playPauseAction},
+		stopAction:         transportKey{m, stopAction},
+		nextAction:         transportKey{m, nextAction},
+		previousAction:     transportKey{m, previousAction},
+		shuffleAction:      playModeKey{m, shuffleAction},
+		repeatAction:       playModeKey{m, repeatAction},
+		presetAction:       presetKey{m},
+		sceneAction:        sceneKey{m},
+		tunerBandAction:    tunerKey{m, tunerBandAction},
+		tunerFreqAction:    tunerKey{m, tunerFreqAction},
+		tunerPresetAction:  tunerKey{m, tunerPresetAction},
+		soundProgramAction: soundProgramKey{m},
+		soundToggleAction:  soundToggleKey{m},
+		toneControlAction:  toneControlKey{m},
+		sleepAction:        sleepKey{m},
+		linkAction:         linkKey{m},
+		partyAction:        partyKey{m},
+		dialAction:         volumeDial{m},
 	}
-	log.Printf("Is MusicCast device on? %v", on)
 
-	targetState := 1 // off
-	if on {
-		targetState = 0 // on
+	if m.events != nil {
+		go m.eventWorker()
 	}
-	// delay setState for one second to avoid wrong values, because streamdeck switches button by iteself shortly after press
-	go func() {
-		time.Sleep(time.Second)
-		err = sender.SetState(event.Context, targetState)
-		if err != nil {
-			return
-		}
-		log.Printf("State set to: %v", targetState)
-	}()
 
-	return nil
+	return m
 }
 
-func (m *musicCastHandler) HandleKeyUpEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
-	return nil
+// register routes the events of every action to its own handler
+func (m *musicCastHandler) register(plugin *sdplugin.Plugin) {
+	for action, handler := range m.actionMap {
+		plugin.Register(action, handler)
+	}
 }
 
 func (m *musicCastHandler) HandleWillAppearEvent(sender sdplugin.Sender, event sdplugin.AppearanceEventMessage) error {
 	// load settings once at appearance for UI update
-	settings, err := loadSettings(event.Payload.Settings)
+	settings, err := m.resolveSettings(event.Payload.Settings)
 	if err != nil {
 		return err
 	}
 
 	log.Printf("[willAppear]Settings loaded: %#v\n", settings)
+	m.start(sender)
+
+	// keys configured with the IP of a discovered device store its UUID instead
+	settings, changed := m.adoptDevices(settings)
+	if changed {
+		err = sender.SetSettings(event.Context, settings)
+		if err != nil {
+			log.Printf("Failed to save device: %v\n", err)
+		}
+	}
 
 	// update settings map
 	m.contextMapMutex.Lock()
-	m.contextMap[event.Context] = settings
+	m.contextMap[event.Context] = actionContext{action: event.Action, settings: settings}
 	m.contextMapMutex.Unlock()
 
-	// start background update worker
-	context, cancelFunc := context.WithCancel(context.Background())
-	go m.contextUpdateWorker(context, sender, event.Context)
-
-	// update cancel func map
-	m.cancelMapMutex.Lock()
-	m.cancelMap[event.Context] = cancelFunc
-	m.cancelMapMutex.Unlock()
-
-	return nil
+	// show cached state right away, the poller updates the key later on
+	state := m.subscribe(sender, settings.deviceKey(), event.Context)
+	return m.renderContext(sender, event.Context, actionContext{action: event.Action, settings: settings}, state)
 }
 
 func (m *musicCastHandler) HandleWillDisappearEvent(sender sdplugin.Sender, event sdplugin.AppearanceEventMessage) error {
 	// delete settings
 	m.contextMapMutex.Lock()
+	actionContext, ok := m.contextMap[event.Context]
 	delete(m.contextMap, event.Context)
 	m.contextMapMutex.Unlock()
 
-	m.cancelMapMutex.Lock()
-	cancelFunc := m.cancelMap[event.Context]
-	m.cancelMapMutex.Unlock()
+	if ok {
+		m.unsubscribe(actionContext.settings.deviceKey(), event.Context)
+	}
+
+	m.albumArtMapMutex.Lock()
+	delete(m.albumArtMap, event.Context)
+	m.albumArtMapMutex.Unlock()
 
-	cancelFunc()
+	m.keyDownMapMutex.Lock()
+	delete(m.keyDownMap, event.Context)
+	m.keyDownMapMutex.Unlock()
 
 	return nil
 }
 
-func (m *musicCastHandler) HandleSendToPluginEvent(sender sdplugin.Sender, event sdplugin.SendToPluginEventMessage) error {
-	var propertyInspectorMessageType propertyInspectorMessageType
-	err := json.Unmarshal(event.Payload, &propertyInspectorMessageType)
+// HandleDidReceiveSettingsEvent is sent when the property inspector changed the settings
+func (m *musicCastHandler) HandleDidReceiveSettingsEvent(sender sdplugin.Sender, event sdplugin.DidReceiveSettingsEventMessage) error {
+	settings, err := m.resolveSettings(event.Payload.Settings)
 	if err != nil {
 		return err
 	}
 
-	// send settings to UI at startup
-	if propertyInspectorMessageType.Type == "startup" {
-		// get settings from map
-		m.contextMapMutex.Lock()
-		defer m.contextMapMutex.Unlock()
-		if settings, ok := m.contextMap[event.Context]; ok {
-			// settings request by property view
-			err = sender.SendToPropertyInspector(event.Context, "de.louischrist.musiccast.power", &settings)
-			if err != nil {
-				return err
-			}
-		}
-	} else {
-		// settings update from UI
-		settings, err := loadSettings(event.Payload)
-		if err != nil {
-			return err
-		}
+	// a discovered device entered by IP is stored by UUID
+	settings, changed := m.adoptDevices(settings)
 
-		err = sender.SetSettings(event.Context, settings)
+	// remember identity of the device to find it again if its IP changes
+	m.contextMapMutex.Lock()
+	oldActionContext := m.contextMap[event.Context]
+	m.contextMapMutex.Unlock()
+	ip := settings.deviceKey().ip
+	if ip != oldActionContext.settings.deviceKey().ip || settings.DeviceID == "" {
+		deviceID := m.deviceID(ip)
+		changed = changed || deviceID != settings.DeviceID
+		settings.DeviceID = deviceID
+	}
+
+	// settings are only written back if the plugin changed them,
+	// otherwise they could overwrite newer edits of the property inspector
+	if changed {
+		err = m.updateSettings(sender, event.Context, settings)
 		if err != nil {
 			return err
 		}
-
-		// update settings map
-		m.contextMapMutex.Lock()
-		m.contextMap[event.Context] = settings
-		m.contextMapMutex.Unlock()
+	} else {
+		m.moveContext(sender, event.Context, settings)
 	}
+	m.refreshDevice(settings.deviceKey())
 
-	return nil
+	// device may have changed, send its choices again
+	return m.sendPropertyInspectorData(sender, event.Context, event.Action, settings)
 }
 
-func (m *musicCastHandler) HandleTitleParametersDidChangeEvent(sender sdplugin.Sender, event sdplugin.TitleParametersDidChangeEventMessage) error {
-	return nil
-}
+// HandlePropertyInspectorDidAppearEvent sends the devices and the choices of the configured device
+func (m *musicCastHandler) HandlePropertyInspectorDidAppearEvent(sender sdplugin.Sender, event sdplugin.PropertyInspectorEventMessage) error {
+	m.propertyInspectorMapMutex.Lock()
+	m.propertyInspectorMap[event.Context] = true
+	m.propertyInspectorMapMutex.Unlock()
 
-func (m *musicCastHandler) HandleDeviceDidConnectEvent(sender sdplugin.Sender, event sdplugin.DeviceDidConnectEventMessage) error {
-	return nil
-}
+	m.contextMapMutex.Lock()
+	actionContext, ok := m.contextMap[event.Context]
+	m.contextMapMutex.Unlock()
+	if !ok {
+		return nil
+	}
 
-func (m *musicCastHandler) HandleDeviceDidDisconnectEvent(sender sdplugin.Sender, event sdplugin.DeviceDidDisconnectEventMessage) error {
+	err := m.sendPropertyInspectorData(sender, event.Context, event.Action, actionContext.settings)
+	if err != nil {
+		return err
+	}
+
+	// search for new devices and send them afterwards if the property inspector is still open
+	go func() {
+		m.discoverDevices(sender)
+
+		m.propertyInspectorMapMutex.Lock()
+		visible := m.propertyInspectorMap[event.Context]
+		m.propertyInspectorMapMutex.Unlock()
+		m.contextMapMutex.Lock()
+		actionContext, ok := m.contextMap[event.Context]
+		m.contextMapMutex.Unlock()
+		if visible && ok {
+			err := m.sendPropertyInspectorData(sender, event.Context, event.Action, actionContext.settings)
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}()
 	return nil
 }
 
-func (m *musicCastHandler) HandleApplicationDidLaunchEvent(sender sdplugin.Sender, event sdplugin.ApplicationEventMessage) error {
+func (m *musicCastHandler) HandlePropertyInspectorDidDisappearEvent(sender sdplugin.Sender, event sdplugin.PropertyInspectorEventMessage) error {
+	m.propertyInspectorMapMutex.Lock()
+	delete(m.propertyInspectorMap, event.Context)
+	m.propertyInspectorMapMutex.Unlock()
 	return nil
 }
 
-func (m *musicCastHandler) HandleApplicationDidTerminateEvent(sender sdplugin.Sender, event sdplugin.ApplicationEventMessage) error {
+// HandleSystemDidWakeUpEvent updates all keys, their state is outdated after the computer slept
+func (m *musicCastHandler) HandleSystemDidWakeUpEvent(sender sdplugin.Sender, event sdplugin.SystemDidWakeUpEventMessage) error {
+	// event subscriptions most likely expired during sleep, poll until events arrive again
+	m.lastEventMapMutex.Lock()
+	m.lastEventMap = make(map[string]time.Time)
+	m.lastEventMapMutex.Unlock()
+
+	m.refreshAllDevices()
 	return nil
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// deviceID returns the id of the device at ip or an empty string if it does not answer
+func (m *musicCastHandler) deviceID(ip string) string {
+	if ip == "" {
+		return ""
+	}
+
+	info, err := musiccast.NewClient(ip, m.httpClient).GetDeviceInfo(context.Background())
+	if err != nil {
+		log.Printf("Could not fetch device info: %v\n", err)
+		return ""
+	}
+	return info.DeviceID
+}
+
+// verifyDevice checks that the device zone is still the device the subscribed contexts were configured for.
+// Contexts of devices which changed their IP are moved to the new IP.
+// Contexts without a device id get the id of the device.
+// Returns true if the device answered with the expected id.
+func (m *musicCastHandler) verifyDevice(ctx context.Context, sender sdplugin.Sender, key deviceKey) bool {
+	info, err := m.deviceFor(key).GetDeviceInfo(ctx)
+	if err != nil {
+		log.Printf("Could not verify device %v: %v\n", key.ip, err)
+	}
+
+	verified := err == nil
+	lostDeviceIDs := make(map[string]bool)
+	for context, actionContext := range m.subscribedContexts(key) {
+		settings := actionContext.settings
+		switch {
+		case err == nil && settings.DeviceID == "":
+			// remember identity for the next IP change
+			settings.DeviceID = info.DeviceID
+			err := m.updateSettings(sender, context, settings)
+			if err != nil {
+				log.Printf("Failed to save device id: %v\n", err)
+			}
+		case settings.DeviceID != "" && (err != nil || settings.DeviceID != info.DeviceID):
+			lostDeviceIDs[settings.DeviceID] = true
+			verified = false
+		}
+	}
+
+	for deviceID := range lostDeviceIDs {
+		device, ok := m.resolveDeviceID(ctx, sender, deviceID)
+		if !ok || device.Host == key.ip {
+			continue
+		}
+		log.Printf("Device %v moved from %v to %v\n", deviceID, key.ip, device.Host)
+		m.moveDevice(sender, deviceID, device)
+	}
+
+	return verified
+}
+
+// resolveDeviceID searches the network for the device with the given id.
+// Contexts configured with the UUID of the device follow it by the discovery already.
+func (m *musicCastHandler) resolveDeviceID(ctx context.Context, sender sdplugin.Sender, deviceID string) (musiccast.DiscoveredDevice, bool) {
+	for _, device := range m.discoverDevices(sender) {
+		info, err := musiccast.NewClient(device.Host, m.httpClient).GetDeviceInfo(ctx)
+		if err != nil {
+			continue
+		}
+		if info.DeviceID == deviceID {
+			return device, true
+		}
+	}
+	return musiccast.DiscoveredDevice{}, false
+}
+
+// moveDevice configures all contexts of the device with the given id, which were configured by IP,
+// with the UUID of the discovered device. Its address is only stored in the global settings from now on.
+func (m *musicCastHandler) moveDevice(sender sdplugin.Sender, deviceID string, device musiccast.DiscoveredDevice) {
+	m.contextMapMutex.Lock()
+	moved := make(map[string]Settings)
+	for context, actionContext := range m.contextMap {
+		if actionContext.settings.DeviceID == deviceID && actionContext.settings.Device != device.UUID {
+			settings := actionContext.settings
+			settings.Device = device.UUID
+			settings.IP = ""
+			moved[context] = settings
+		}
+	}
+	m.contextMapMutex.Unlock()
+
+	for context, settings := range moved {
+		err := m.updateSettings(sender, context, m.resolveDevice(settings))
+		if err != nil {
+			log.Printf("Failed to save device: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// inputKey switches a device to an input
+type inputKey struct {
+	handler *musicCastHandler
+}
+
+func (a inputKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a inputKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	return sender.SetState(context, inputState(state.status, actionContext.settings.Input))
+}
+
+// keyDown switches the device to the configured input
+func (a inputKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	if settings.Input == "" {
+		sender.ShowAlert(sdContext)
+		return errors.New("No input configured")
+	}
+
+	device := a.handler.device(settings)
+	err := device.SetInput(context.Background(), settings.Input)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	setStateDelayed(sender, sdContext, inputState(status, settings.Input))
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return nil
+}
+
+// inputState returns the streamdeck state for the current input of the device
+func inputState(status *musiccast.Status, input string) int {
+	if status.Input == input {
+		return 0 // selected
+	}
+	return 1 // not selected
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// linkKey links rooms to a device
+type linkKey struct {
+	handler *musicCastHandler
+}
+
+func (a linkKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a linkKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	if state.distInfo == nil {
+		return nil
+	}
+	return sender.SetState(context, linkState(state.distInfo, a.handler.hosts(actionContext.settings.Clients)))
+}
+
+// keyDown links the configured rooms to the device of the key or dissolves the group if they are linked already
+func (a linkKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	clients := a.handler.hosts(settings.Clients)
+	if len(clients) == 0 {
+		sender.ShowAlert(sdContext)
+		return errors.New("No rooms configured")
+	}
+
+	key := settings.deviceKey()
+	distInfo, err := a.handler.deviceFor(key).GetDistributionInfo(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	if isLinked(distInfo, clients) {
+		err = a.handler.unlinkDevices(key, distInfo)
+	} else {
+		err = a.handler.linkDevices(key, clients)
+	}
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	distInfo, err = a.handler.deviceFor(key).GetDistributionInfo(context.Background())
+	if err != nil {
+		return err
+	}
+
+	setStateDelayed(sender, sdContext, linkState(distInfo, clients))
+	a.handler.deviceChanged(sender, key, deviceState{distInfo: distInfo}, sdContext)
+	return nil
+}
+
+// linkDevices creates a MusicCast Link group with the device zone as master
+// and the main zones of the clients, then starts distributing its audio.
+// If a device fails, the devices configured so far are reset.
+func (m *musicCastHandler) linkDevices(master deviceKey, clients []string) error {
+	groupID, err := newGroupID()
+	if err != nil {
+		return err
+	}
+
+	// clients have to know the group before the master adds them
+	linked := make([]string, 0, len(clients))
+	for _, client := range clients {
+		err := musiccast.NewClient(client, m.httpClient).SetClientInfo(context.Background(), musiccast.ClientInfo{
+			GroupID:         groupID,
+			Zone:            []string{musiccast.ZoneMain},
+			ServerIPAddress: master.ip,
+		})
+		if err != nil {
+			// do not leave the other clients waiting for a group that never starts
+			m.resetClients(linked)
+			return err
+		}
+		linked = append(linked, client)
+	}
+
+	device := m.deviceFor(master)
+	err = device.SetServerInfo(context.Background(), musiccast.ServerInfo{
+		GroupID:    groupID,
+		Zone:       device.Zone(),
+		Type:       musiccast.ServerInfoAdd,
+		ClientList: clients,
+	})
+	if err != nil {
+		m.resetClients(linked)
+		return err
+	}
+
+	err = device.StartDistribution(context.Background(), 0)
+	if err != nil {
+		resetErr := device.SetServerInfo(context.Background(), musiccast.ServerInfo{})
+		if resetErr != nil {
+			log.Printf("Could not reset master %v: %v\n", master.ip, resetErr)
+		}
+		m.resetClients(linked)
+		return err
+	}
+	return nil
+}
+
+// resetClients removes the clients from their group. Clients which are gone are skipped.
+func (m *musicCastHandler) resetClients(clients []string) {
+	for _, client := range clients {
+		err := musiccast.NewClient(client, m.httpClient).SetClientInfo(context.Background(), musiccast.ClientInfo{})
+		if err != nil {
+			log.Printf("Could not unlink %v: %v\n", client, err)
+		}
+	}
+}
+
+// unlinkDevices dissolves the MusicCast Link group of the master
+func (m *musicCastHandler) unlinkDevices(master deviceKey, distInfo *musiccast.DistributionInfo) error {
+	// the clients may be gone, dissolve the group anyway
+	clients := make([]string, 0, len(distInfo.ClientList))
+	for _, client := range distInfo.ClientList {
+		clients = append(clients, client.IPAddress)
+	}
+	m.resetClients(clients)
+
+	device := m.deviceFor(master)
+	err := device.StopDistribution(context.Background())
+	if err != nil {
+		return err
+	}
+	return device.SetServerInfo(context.Background(), musiccast.ServerInfo{})
+}
+
+// newGroupID returns a random MusicCast Link group id of 32 hex digits
+func newGroupID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// isLinked reports if the device is master of a group with all clients
+func isLinked(distInfo *musiccast.DistributionInfo, clients []string) bool {
+	if distInfo.Role != musiccast.RoleServer {
+		return false
+	}
+	for _, client := range clients {
+		linked := false
+		for _, linkedClient := range distInfo.ClientList {
+			if linkedClient.IPAddress == client {
+				linked = true
+			}
+		}
+		if !linked {
+			return false
+		}
+	}
+	return true
+}
+
+// linkState returns the streamdeck state for the MusicCast Link group of the device
+func linkState(distInfo *musiccast.DistributionInfo, clients []string) int {
+	if isLinked(distInfo, clients) {
+		return 0 // linked
+	}
+	return 1 // unlinked
+}
+
+// needsDistributionInfo reports if the action shows the MusicCast Link state
+func needsDistributionInfo(action string) bool {
+	return action == linkAction || action == partyAction
+}
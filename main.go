@@ -14,10 +14,12 @@ func main() {
 	// defer file.Close()
 	// log.SetOutput(file)
 
-	plugin, err := sdplugin.New(newMusicCastHandler())
+	handler := newMusicCastHandler()
+	plugin, err := sdplugin.New(handler)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer plugin.Close()
+	handler.register(plugin)
 	log.Fatal(plugin.Run())
 }
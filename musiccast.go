@@ -3,18 +3,57 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
-	"time"
 
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
 	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
 )
 
 //Settings data for plugin
 type Settings struct {
+	// Device is the UUID of a device in the global settings, its address is resolved from there
+	Device string `json:"Device,omitempty"`
+	// IP of a device which was not discovered, only used if Device is empty
 	IP string `json:"IP"`
+	// Volume for the set volume action, nil if not configured
+	Volume *int `json:"Volume,omitempty"`
+	// Step for volume up and down. The device default is used if 0.
+	Step int `json:"Step,omitempty"`
+	// Input for the input action, e.g. hdmi1 or net_radio
+	Input string `json:"Input,omitempty"`
+	// Preset number for the preset action, starting with 1
+	Preset int `json:"Preset,omitempty"`
+	// Scene number for the scene action, starting with 1
+	Scene int `json:"Scene,omitempty"`
+	// Band for the tuner actions, the current band if empty
+	Band string `json:"Band,omitempty"`
+	// Tuning mode for the tuner frequency action, auto_up if empty
+	Tuning string `json:"Tuning,omitempty"`
+	// Frequency in kHz for direct tuning
+	Frequency int `json:"Frequency,omitempty"`
+	// SoundProgram for the sound program action, e.g. straight or movie
+	SoundProgram string `json:"SoundProgram,omitempty"`
+	// SoundProgramMode is "cycle" to step through SoundPrograms, otherwise SoundProgram is selected
+	SoundProgramMode string `json:"SoundProgramMode,omitempty"`
+	// SoundPrograms the sound program action cycles through
+	SoundPrograms []string `json:"SoundPrograms,omitempty"`
+	// SurroundDecoder the sound program action selects with the surround decoder program, e.g. dolby_pl2x_movie
+	SurroundDecoder string `json:"SurroundDecoder,omitempty"`
+	// Function the sound toggle action switches, e.g. pure_direct or enhancer
+	Function string `json:"Function,omitempty"`
+	// Tone the tone control action changes, bass or treble
+	Tone string `json:"Tone,omitempty"`
+	// Direction the tone control action changes the tone in, up or down
+	Direction string `json:"Direction,omitempty"`
+	// Clients are the rooms the link action links to this device,
+	// UUIDs of devices in the global settings or IPs of devices which were not discovered
+	Clients []string `json:"Clients,omitempty"`
+	// Zone of the device, main if empty
+	Zone string `json:"Zone,omitempty"`
+	// DeviceID of the device at IP, used to find the device again if its IP changes
+	DeviceID string `json:"DeviceID,omitempty"`
+
+	// host of the device resolved from Device or IP, it is not saved
+	host string
 }
 
 //loadSettings from data or return erro if failed
@@ -27,117 +66,108 @@ func loadSettings(payload []byte) (Settings, error) {
 	return settings, nil
 }
 
-// contextUpdateWorker keeps the devices power state up to date
-func (m *musicCastHandler) contextUpdateWorker(context context.Context, sender sdplugin.Sender, sdContext string) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	log.Println("Starting worker")
-	for true {
-		select {
-		case <-ticker.C:
-			log.Println("Worker update")
-			m.stateUpdate(sender, sdContext)
-		case <-context.Done():
-			log.Println("Stopping worker")
-			return
-		}
+// updateSettings of a context. The settings are saved by streamdeck and
+// the context is moved to the poller of its new device zone.
+func (m *musicCastHandler) updateSettings(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	err := sender.SetSettings(sdContext, settings)
+	if err != nil {
+		return err
 	}
+
+	m.moveContext(sender, sdContext, settings)
+	return nil
 }
 
-// stateUpdate fetches status from musiccast device and updates streamdeck icon
-func (m *musicCastHandler) stateUpdate(sender sdplugin.Sender, context string) {
+// moveContext stores the settings of a context and moves it to the poller of its new device zone
+func (m *musicCastHandler) moveContext(sender sdplugin.Sender, sdContext string, settings Settings) {
+	// update settings map
 	m.contextMapMutex.Lock()
-	defer m.contextMapMutex.Unlock()
-	if settings, ok := m.contextMap[context]; ok {
-		on, err := m.isMusicCastPowerOn(settings.IP)
-		if err != nil {
-			log.Printf("Could not check device power status: %v\n", err)
-			return
-		}
-
-		log.Printf("Is on? %v\n", on)
-
-		targetState := 1 //off
-		if on {
-			targetState = 0 //on
-		}
-		log.Printf("Settings state to %v\n", targetState)
-		err = sender.SetState(context, targetState)
-		if err != nil {
-			log.Printf("Failed to set device state: %v\n", err)
-			return
-		}
+	oldActionContext, ok := m.contextMap[sdContext]
+	if ok {
+		m.contextMap[sdContext] = actionContext{action: oldActionContext.action, settings: settings}
 	}
-}
+	m.contextMapMutex.Unlock()
 
-func (m *musicCastHandler) setMusicCastPower(ip string, on bool) error {
-	desiredState := "standby"
-	if on {
-		desiredState = "on"
+	// move context to poller of the new device zone
+	if ok && oldActionContext.settings.deviceKey() != settings.deviceKey() {
+		m.unsubscribe(oldActionContext.settings.deviceKey(), sdContext)
+		m.subscribe(sender, settings.deviceKey(), sdContext)
 	}
+}
 
-	resp, err := m.client.Get(fmt.Sprintf("http://%v/YamahaExtendedControl/v1/main/setPower?power=%v", ip, desiredState))
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Got wrong status code %v", resp.Status)
+// features of the device at ip. Features do not change, so they are fetched only once.
+func (m *musicCastHandler) features(ip string) (*musiccast.Features, error) {
+	m.featureMapMutex.Lock()
+	features, ok := m.featureMap[ip]
+	m.featureMapMutex.Unlock()
+	if ok {
+		return features, nil
 	}
-	return nil
-}
 
-func (m *musicCastHandler) musicCastPowerToggle(ip string) error {
-	resp, err := m.client.Get(fmt.Sprintf("http://%v/YamahaExtendedControl/v1/main/setPower?power=toggle", ip))
+	features, err := musiccast.NewClient(ip, m.httpClient).GetFeatures(context.Background())
 	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Got wrong status code %v", resp.Status)
+		return nil, err
 	}
-	return nil
+
+	m.featureMapMutex.Lock()
+	m.featureMap[ip] = features
+	m.featureMapMutex.Unlock()
+	return features, nil
 }
 
-// musicCastStatus struct for getStatus response from MusicCast device.
-// Only needed field are present,
-type musicCastStatus struct {
-	Code  int    `json:"response_code"`
-	Power string `json:"power"` // standby or on
+// deviceKey identifies a zone of a MusicCast device
+type deviceKey struct {
+	ip   string
+	zone string
 }
 
-func (m *musicCastHandler) isMusicCastPowerOn(ip string) (bool, error) {
-	resp, err := m.client.Get(fmt.Sprintf("http://%v/YamahaExtendedControl/v1/main/getStatus", ip))
-	if err != nil {
-		return false, err
+// deviceKey returns the device and zone configured in settings
+func (s Settings) deviceKey() deviceKey {
+	zone := s.Zone
+	if zone == "" {
+		zone = musiccast.ZoneMain
 	}
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("Got wrong status code %v", resp.Status)
+	host := s.host
+	if host == "" {
+		host = s.IP
 	}
+	return deviceKey{ip: host, zone: zone}
+}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return false, err
-	}
-	resp.Body.Close()
+// device returns a client for the MusicCast device and zone configured in settings
+func (m *musicCastHandler) device(settings Settings) *musiccast.Client {
+	return m.deviceFor(settings.deviceKey())
+}
 
-	var musicCastStatus musicCastStatus
-	err = json.Unmarshal(data, &musicCastStatus)
-	if err != nil {
-		return false, err
+// deviceFor returns a client for the MusicCast device zone.
+// Every request of the client subscribes the device to events.
+func (m *musicCastHandler) deviceFor(key deviceKey) *musiccast.Client {
+	client := musiccast.NewClient(key.ip, m.httpClient).WithZone(key.zone)
+	if m.events != nil {
+		client = client.WithEvents("streamdeck", m.events.Port())
 	}
+	return client
+}
 
-	if musicCastStatus.Code != 0 {
-		return false, fmt.Errorf("Error code: %v", musicCastStatus.Code)
+// renderContext shows the device state on the key with the renderer of its action.
+// Actions without a renderer, like scenes, keep their icon.
+func (m *musicCastHandler) renderContext(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	if state.status == nil {
+		// nothing fetched yet
+		return nil
 	}
 
-	if musicCastStatus.Power == "standby" {
-		return false, nil
+	if r, ok := m.actionMap[actionContext.action].(renderer); ok {
+		return r.render(sender, context, actionContext, state)
 	}
-
-	return true, nil
+	return nil
 }
 
-// propertyInspectorMessageType is used to differentiate between get and startup messages
-type propertyInspectorMessageType struct {
-	Type string `json:"type"`
+// needsPlayInfo reports if the action shows net/usb or CD play info
+func needsPlayInfo(action string) bool {
+	switch action {
+	case nowPlayingAction, playPauseAction, shuffleAction, repeatAction:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,59 @@
+package musiccast
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// CDPlayInfo is the response of cd/getPlayInfo
+type CDPlayInfo struct {
+	Response
+	DeviceStatus string `json:"device_status"` // open, close, ready or not_ready
+	Playback     string `json:"playback"`
+	Repeat       string `json:"repeat"`
+	Shuffle      string `json:"shuffle"`
+	PlayTime     int    `json:"play_time"`
+	TotalTime    int    `json:"total_time"`
+	DiscTime     int    `json:"disc_time"`
+	TrackNumber  int    `json:"track_number"`
+	TotalTracks  int    `json:"total_tracks"`
+	Artist       string `json:"artist"`
+	Album        string `json:"album"`
+	Track        string `json:"track"`
+}
+
+// GetCDPlayInfo returns what the CD player is currently playing
+func (c *Client) GetCDPlayInfo(ctx context.Context) (*CDPlayInfo, error) {
+	var info CDPlayInfo
+	err := c.get(ctx, "cd/getPlayInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SetCDPlayback sends a playback command, see the Playback constants
+func (c *Client) SetCDPlayback(ctx context.Context, playback string) error {
+	return c.get(ctx, "cd/setPlayback", url.Values{"playback": {playback}}, nil)
+}
+
+// SelectCDTrack plays the track with the given number
+func (c *Client) SelectCDTrack(ctx context.Context, num int) error {
+	return c.get(ctx, "cd/setPlayback", url.Values{"playback": {"track_select"}, "num": {strconv.Itoa(num)}}, nil)
+}
+
+// ToggleCDTray opens or closes the tray
+func (c *Client) ToggleCDTray(ctx context.Context) error {
+	return c.get(ctx, "cd/toggleTray", nil, nil)
+}
+
+// ToggleCDRepeat cycles the repeat mode
+func (c *Client) ToggleCDRepeat(ctx context.Context) error {
+	return c.get(ctx, "cd/toggleRepeat", nil, nil)
+}
+
+// ToggleCDShuffle cycles the shuffle mode
+func (c *Client) ToggleCDShuffle(ctx context.Context) error {
+	return c.get(ctx, "cd/toggleShuffle", nil, nil)
+}
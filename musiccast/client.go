@@ -0,0 +1,150 @@
+// Package musiccast implements a client for the Yamaha Extended Control (YXC)
+// API v1 spoken by MusicCast devices.
+//
+// Create a Client with NewClient(host, httpClient). Zone related calls target
+// the zone of the client, which defaults to ZoneMain and can be changed with
+// WithZone(zone).
+package musiccast
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Zones of a MusicCast device. Not every device supports all zones,
+// see Features for the zones a device exposes.
+const (
+	ZoneMain = "main"
+	Zone2    = "zone2"
+	Zone3    = "zone3"
+	Zone4    = "zone4"
+)
+
+// Client talks to a single MusicCast device.
+// All methods are threadsafe.
+type Client struct {
+	host       string
+	baseURL    string
+	httpClient *http.Client
+	zone       string
+	// headers subscribing to events, see WithEvents
+	eventHeaders map[string]string
+}
+
+// NewClient for the device reachable at host. If httpClient is nil
+// http.DefaultClient is used.
+func NewClient(host string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		host:       host,
+		baseURL:    fmt.Sprintf("http://%v/YamahaExtendedControl/v1", host),
+		httpClient: httpClient,
+		zone:       ZoneMain,
+	}
+}
+
+// Host the client is connected to
+func (c *Client) Host() string {
+	return c.host
+}
+
+// Zone used for zone related calls
+func (c *Client) Zone() string {
+	return c.zone
+}
+
+// WithZone returns a copy of the client targeting the given zone.
+// An empty zone selects ZoneMain.
+func (c *Client) WithZone(zone string) *Client {
+	if zone == "" {
+		zone = ZoneMain
+	}
+	client := *c
+	client.zone = zone
+	return &client
+}
+
+// responder is implemented by every response through the embedded Response
+type responder interface {
+	responseCode() ResponseCode
+}
+
+// Response contains the fields every YXC response carries
+type Response struct {
+	ResponseCode ResponseCode `json:"response_code"`
+}
+
+func (r *Response) responseCode() ResponseCode {
+	return r.ResponseCode
+}
+
+// get calls the endpoint at path with query and decodes the answer into v.
+// If v is nil only the response code is checked.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v responder) error {
+	endpoint := c.baseURL + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, path, v)
+}
+
+// post sends body as JSON to the endpoint at path and decodes the answer into v.
+// If v is nil only the response code is checked.
+func (c *Client) post(ctx context.Context, path string, body interface{}, v responder) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, path, v)
+}
+
+func (c *Client) do(req *http.Request, path string, v responder) error {
+	for key, value := range c.eventHeaders {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Got wrong status code %v", resp.Status)
+	}
+
+	if v == nil {
+		v = &Response{}
+	}
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return err
+	}
+
+	if code := v.responseCode(); code != CodeOK {
+		return &Error{Endpoint: path, Code: code}
+	}
+	return nil
+}
+
+// zonePath returns the path of a zone endpoint for the zone of the client
+func (c *Client) zonePath(endpoint string) string {
+	return c.zone + "/" + endpoint
+}
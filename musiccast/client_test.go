@@ -0,0 +1,121 @@
+package musiccast
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestClient returns a client for a server answering every request with body
+func newTestClient(t *testing.T, status int, body string) (*Client, *http.Request) {
+	t.Helper()
+
+	var request http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = *r.Clone(context.Background())
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(strings.TrimPrefix(server.URL, "http://"), server.Client()), &request
+}
+
+func TestClientResponseCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode ResponseCode
+		wantErr  bool
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"response_code":0}`},
+		{name: "invalid parameter", status: http.StatusOK, body: `{"response_code":4}`, wantCode: CodeInvalidParameter, wantErr: true},
+		{name: "guarded", status: http.StatusOK, body: `{"response_code":5}`, wantCode: CodeGuarded, wantErr: true},
+		{name: "linking", status: http.StatusOK, body: `{"response_code":200}`, wantCode: CodeLinkingInProgress, wantErr: true},
+		{name: "http error", status: http.StatusNotFound, body: ``, wantErr: true},
+		{name: "garbage", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, _ := newTestClient(t, test.status, test.body)
+			err := client.SetPower(context.Background(), PowerOn)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("SetPower() error = %v, want error %v", err, test.wantErr)
+			}
+			if test.wantCode == CodeOK {
+				return
+			}
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("SetPower() error = %#v, want *Error", err)
+			}
+			if apiErr.Endpoint != "main/setPower" {
+				t.Errorf("Endpoint = %q, want %q", apiErr.Endpoint, "main/setPower")
+			}
+			if !errors.Is(err, test.wantCode) {
+				t.Errorf("errors.Is(%v, %v) = false", err, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestClientRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		zone      string
+		events    bool
+		wantPath  string
+		wantQuery string
+	}{
+		{name: "main zone", wantPath: "/YamahaExtendedControl/v1/main/setPower", wantQuery: "power=on"},
+		{name: "zone2", zone: Zone2, wantPath: "/YamahaExtendedControl/v1/zone2/setPower", wantQuery: "power=on"},
+		{name: "events", events: true, wantPath: "/YamahaExtendedControl/v1/main/setPower", wantQuery: "power=on"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, request := newTestClient(t, http.StatusOK, `{"response_code":0}`)
+			client = client.WithZone(test.zone)
+			if test.events {
+				client = client.WithEvents("test", 41100)
+			}
+
+			err := client.SetPower(context.Background(), PowerOn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if request.URL.Path != test.wantPath {
+				t.Errorf("path = %q, want %q", request.URL.Path, test.wantPath)
+			}
+			if request.URL.RawQuery != test.wantQuery {
+				t.Errorf("query = %q, want %q", request.URL.RawQuery, test.wantQuery)
+			}
+
+			wantPort := ""
+			if test.events {
+				wantPort = "41100"
+			}
+			if port := request.Header.Get("X-AppPort"); port != wantPort {
+				t.Errorf("X-AppPort = %q, want %q", port, wantPort)
+			}
+		})
+	}
+}
+
+func TestClientGetStatus(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusOK, `{"response_code":0,"power":"on","volume":42,"mute":true,"max_volume":161,"input":"net_radio"}`)
+
+	status, err := client.GetStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.IsOn() || status.Volume != 42 || !status.Mute || status.MaxVolume != 161 || status.Input != "net_radio" {
+		t.Errorf("GetStatus() = %+v", status)
+	}
+}
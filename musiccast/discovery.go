@@ -0,0 +1,199 @@
+package musiccast
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SSDPAddress is the multicast address SSDP searches are sent to
+const SSDPAddress = "239.255.255.250:1900"
+
+// searchTarget of the M-SEARCH request. All MusicCast devices are media renderers.
+const searchTarget = "urn:schemas-upnp-org:device:MediaRenderer:1"
+
+// yxcSpecType identifies the Yamaha Extended Control service in the device description
+const yxcSpecType = "urn:schemas-yamaha-com:service:X_YamahaExtendedControl:1"
+
+// DiscoveredDevice is a MusicCast device found by a Discoverer
+type DiscoveredDevice struct {
+	// Host to pass to NewClient
+	Host         string `json:"host"`
+	FriendlyName string `json:"friendlyName"`
+	ModelName    string `json:"modelName"`
+	UUID         string `json:"uuid"`
+}
+
+// Discoverer finds MusicCast devices in the local network with SSDP.
+// The zero value searches SSDPAddress for one second.
+type Discoverer struct {
+	// Address the M-SEARCH request is sent to, SSDPAddress if empty
+	Address string
+	// Timeout waiting for responses, one second if 0
+	Timeout time.Duration
+	// HTTPClient fetching the device descriptions, http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+// Discover MusicCast devices with the default Discoverer
+func Discover(ctx context.Context) ([]DiscoveredDevice, error) {
+	return (&Discoverer{}).Discover(ctx)
+}
+
+// Discover sends an M-SEARCH request, fetches the description of every responding
+// device and returns all Yamaha MusicCast devices sorted by name
+func (d *Discoverer) Discover(ctx context.Context) ([]DiscoveredDevice, error) {
+	locations, err := d.search(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := []DiscoveredDevice{}
+	seen := make(map[string]bool)
+	for _, location := range locations {
+		device, err := d.describe(ctx, location)
+		if err != nil || device == nil || seen[device.UUID] {
+			// not reachable or not a MusicCast device
+			continue
+		}
+		seen[device.UUID] = true
+		devices = append(devices, *device)
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].FriendlyName < devices[j].FriendlyName
+	})
+	return devices, nil
+}
+
+// search sends the M-SEARCH request and collects the description locations of all responses
+func (d *Discoverer) search(ctx context.Context) ([]string, error) {
+	address := d.Address
+	if address == "" {
+		address = SSDPAddress
+	}
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = time.Second
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request := "M-SEARCH * HTTP/1.1\r\n" +
+		fmt.Sprintf("HOST: %v\r\n", address) +
+		"MAN: \"ssdp:discover\"\r\n" +
+		fmt.Sprintf("MX: %d\r\n", int((timeout+time.Second-1)/time.Second)) +
+		fmt.Sprintf("ST: %v\r\n", searchTarget) +
+		"\r\n"
+	_, err = conn.WriteToUDP([]byte(request), udpAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetReadDeadline(deadline)
+
+	locations := []string{}
+	seen := make(map[string]bool)
+	buffer := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			// read deadline reached
+			break
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buffer[:n])), nil)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		if location != "" && !seen[location] {
+			seen[location] = true
+			locations = append(locations, location)
+		}
+	}
+
+	return locations, ctx.Err()
+}
+
+// deviceDescription contains the needed parts of the UPnP device description
+type deviceDescription struct {
+	Device struct {
+		FriendlyName string `xml:"friendlyName"`
+		ModelName    string `xml:"modelName"`
+		UDN          string `xml:"UDN"`
+	} `xml:"device"`
+	YamahaDevice struct {
+		Services []struct {
+			SpecType string `xml:"X_specType"`
+		} `xml:"X_serviceList>X_service"`
+	} `xml:"urn:schemas-yamaha-com:device-1-0 X_device"`
+}
+
+// describe fetches the device description at location.
+// Returns nil if it is not a MusicCast device.
+func (d *Discoverer) describe(ctx context.Context, location string) (*DiscoveredDevice, error) {
+	httpClient := d.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	locationURL, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Got wrong status code %v", resp.Status)
+	}
+
+	var description deviceDescription
+	err = xml.NewDecoder(resp.Body).Decode(&description)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range description.YamahaDevice.Services {
+		if service.SpecType == yxcSpecType {
+			return &DiscoveredDevice{
+				Host:         locationURL.Hostname(),
+				FriendlyName: description.Device.FriendlyName,
+				ModelName:    description.Device.ModelName,
+				UUID:         strings.TrimPrefix(description.Device.UDN, "uuid:"),
+			}, nil
+		}
+	}
+	return nil, nil
+}
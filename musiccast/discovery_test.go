@@ -0,0 +1,140 @@
+package musiccast
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// description returns the UPnP device description of a media renderer.
+// yxc adds the Yamaha Extended Control service.
+func description(name string, uuid string, yxc bool) string {
+	yamaha := ""
+	if yxc {
+		yamaha = `
+  <yamaha:X_device xmlns:yamaha="urn:schemas-yamaha-com:device-1-0">
+    <yamaha:X_serviceList>
+      <yamaha:X_service>
+        <yamaha:X_specType>urn:schemas-yamaha-com:service:X_YamahaRemoteControl:1</yamaha:X_specType>
+      </yamaha:X_service>
+      <yamaha:X_service>
+        <yamaha:X_specType>urn:schemas-yamaha-com:service:X_YamahaExtendedControl:1</yamaha:X_specType>
+      </yamaha:X_service>
+    </yamaha:X_serviceList>
+  </yamaha:X_device>`
+	}
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <friendlyName>%v</friendlyName>
+    <modelName>Model %v</modelName>
+    <UDN>uuid:%v</UDN>
+  </device>%v
+</root>`, name, name, uuid, yamaha)
+}
+
+// startResponder answers every M-SEARCH request on localhost with one response per location
+func startResponder(t *testing.T, locations []string) string {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 4096)
+		for {
+			n, addr, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			if !strings.HasPrefix(string(buffer[:n]), "M-SEARCH * HTTP/1.1\r\n") {
+				continue
+			}
+
+			for _, location := range locations {
+				response := "HTTP/1.1 200 OK\r\n" +
+					"CACHE-CONTROL: max-age=1800\r\n" +
+					"LOCATION: " + location + "\r\n" +
+					"ST: " + searchTarget + "\r\n" +
+					"\r\n"
+				conn.WriteToUDP([]byte(response), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDiscover(t *testing.T) {
+	descriptions := map[string]string{
+		"/living.xml":   description("Living Room", "9ab0c000-f668-11de-9976-00a0ded41bb7", true),
+		"/kitchen.xml":  description("Kitchen", "9ab0c000-f668-11de-9976-00a0de000001", true),
+		"/kitchen2.xml": description("Kitchen", "9ab0c000-f668-11de-9976-00a0de000001", true),
+		"/tv.xml":       description("TV", "5e3c1a2b-0000-0000-0000-000000000000", false),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := descriptions[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	address := startResponder(t, []string{
+		server.URL + "/living.xml",
+		server.URL + "/tv.xml",
+		server.URL + "/kitchen.xml",
+		// same device answering twice with different locations
+		server.URL + "/kitchen2.xml",
+		// duplicate response
+		server.URL + "/living.xml",
+		server.URL + "/missing.xml",
+	})
+
+	discoverer := &Discoverer{
+		Address:    address,
+		Timeout:    200 * time.Millisecond,
+		HTTPClient: server.Client(),
+	}
+	devices, err := discoverer.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []DiscoveredDevice{
+		{Host: "127.0.0.1", FriendlyName: "Kitchen", ModelName: "Model Kitchen", UUID: "9ab0c000-f668-11de-9976-00a0de000001"},
+		{Host: "127.0.0.1", FriendlyName: "Living Room", ModelName: "Model Living Room", UUID: "9ab0c000-f668-11de-9976-00a0ded41bb7"},
+	}
+	if len(devices) != len(want) {
+		t.Fatalf("Discover() = %+v, want %+v", devices, want)
+	}
+	for i := range want {
+		if devices[i] != want[i] {
+			t.Errorf("Discover()[%d] = %+v, want %+v", i, devices[i], want[i])
+		}
+	}
+}
+
+func TestDiscoverNoDevices(t *testing.T) {
+	discoverer := &Discoverer{
+		Address: startResponder(t, nil),
+		Timeout: 100 * time.Millisecond,
+	}
+	devices, err := discoverer.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 0 {
+		t.Errorf("Discover() = %+v, want no devices", devices)
+	}
+}
@@ -0,0 +1,91 @@
+package musiccast
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Roles of a device in a MusicCast Link group
+const (
+	RoleServer = "server"
+	RoleClient = "client"
+	RoleNone   = "none"
+)
+
+// DistributionInfo is the response of dist/getDistributionInfo
+type DistributionInfo struct {
+	Response
+	GroupID      string               `json:"group_id"`
+	GroupName    string               `json:"group_name"`
+	Role         string               `json:"role"`
+	ServerZone   string               `json:"server_zone"`
+	ClientList   []DistributionClient `json:"client_list"`
+	AudioDropout bool                 `json:"audio_dropout"`
+}
+
+// DistributionClient is a client linked to the server
+type DistributionClient struct {
+	IPAddress string `json:"ip_address"`
+	DataType  string `json:"data_type"`
+}
+
+// GetDistributionInfo returns the MusicCast Link state of the device
+func (c *Client) GetDistributionInfo(ctx context.Context) (*DistributionInfo, error) {
+	var info DistributionInfo
+	err := c.get(ctx, "dist/getDistributionInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Types for ServerInfo
+const (
+	ServerInfoAdd    = "add"
+	ServerInfoRemove = "remove"
+)
+
+// ServerInfo is the request body of dist/setServerInfo
+type ServerInfo struct {
+	GroupID    string   `json:"group_id"`
+	Zone       string   `json:"zone,omitempty"`
+	Type       string   `json:"type,omitempty"` // ServerInfoAdd or ServerInfoRemove
+	ClientList []string `json:"client_list,omitempty"`
+}
+
+// SetServerInfo configures the device as server of a group.
+// An empty GroupID dissolves the group.
+func (c *Client) SetServerInfo(ctx context.Context, info ServerInfo) error {
+	return c.post(ctx, "dist/setServerInfo", &info, nil)
+}
+
+// ClientInfo is the request body of dist/setClientInfo
+type ClientInfo struct {
+	GroupID         string   `json:"group_id"`
+	Zone            []string `json:"zone,omitempty"`
+	ServerIPAddress string   `json:"server_ip_address,omitempty"`
+}
+
+// SetClientInfo configures the device as client of a group.
+// An empty GroupID removes the device from its group.
+func (c *Client) SetClientInfo(ctx context.Context, info ClientInfo) error {
+	return c.post(ctx, "dist/setClientInfo", &info, nil)
+}
+
+// StartDistribution starts distributing audio to the configured clients
+func (c *Client) StartDistribution(ctx context.Context, num int) error {
+	return c.get(ctx, "dist/startDistribution", url.Values{"num": {strconv.Itoa(num)}}, nil)
+}
+
+// StopDistribution stops distributing audio
+func (c *Client) StopDistribution(ctx context.Context) error {
+	return c.get(ctx, "dist/stopDistribution", nil, nil)
+}
+
+// SetGroupName sets the display name of the group
+func (c *Client) SetGroupName(ctx context.Context, name string) error {
+	return c.post(ctx, "dist/setGroupName", &struct {
+		Name string `json:"name"`
+	}{Name: name}, nil)
+}
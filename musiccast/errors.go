@@ -0,0 +1,94 @@
+package musiccast
+
+import "fmt"
+
+// ResponseCode is sent by the device with every response. Everything but
+// CodeOK is an error and is returned wrapped in an Error, so callers can
+// check for a specific code with errors.Is(err, musiccast.CodeGuarded).
+type ResponseCode int
+
+// Response codes defined by the YXC API
+const (
+	CodeOK                      ResponseCode = 0
+	CodeInitializing            ResponseCode = 1
+	CodeInternalError           ResponseCode = 2
+	CodeInvalidRequest          ResponseCode = 3
+	CodeInvalidParameter        ResponseCode = 4
+	CodeGuarded                 ResponseCode = 5
+	CodeTimeOut                 ResponseCode = 6
+	CodeFirmwareUpdating        ResponseCode = 99
+	CodeAccessError             ResponseCode = 100
+	CodeOtherErrors             ResponseCode = 101
+	CodeWrongUserName           ResponseCode = 102
+	CodeWrongPassword           ResponseCode = 103
+	CodeAccountExpired          ResponseCode = 104
+	CodeAccountDisconnected     ResponseCode = 105
+	CodeAccountNumberLimit      ResponseCode = 106
+	CodeServerMaintenance       ResponseCode = 107
+	CodeInvalidAccount          ResponseCode = 108
+	CodeLicenseError            ResponseCode = 109
+	CodeReadOnlyMode            ResponseCode = 110
+	CodeMaxStations             ResponseCode = 111
+	CodeAccessDenied            ResponseCode = 112
+	CodeNeedPlaylist            ResponseCode = 113
+	CodeNeedNewPlaylist         ResponseCode = 114
+	CodeSimultaneousLoginsLimit ResponseCode = 115
+	CodeLinkingInProgress       ResponseCode = 200
+	CodeUnlinkingInProgress     ResponseCode = 201
+)
+
+var responseCodeText = map[ResponseCode]string{
+	CodeOK:                      "successful request",
+	CodeInitializing:            "initializing",
+	CodeInternalError:           "internal error",
+	CodeInvalidRequest:          "invalid request",
+	CodeInvalidParameter:        "invalid parameter",
+	CodeGuarded:                 "guarded",
+	CodeTimeOut:                 "time out",
+	CodeFirmwareUpdating:        "firmware updating",
+	CodeAccessError:             "access error",
+	CodeOtherErrors:             "other errors",
+	CodeWrongUserName:           "wrong user name",
+	CodeWrongPassword:           "wrong password",
+	CodeAccountExpired:          "account expired",
+	CodeAccountDisconnected:     "account disconnected/gone off/shut down",
+	CodeAccountNumberLimit:      "account number reached to the limit",
+	CodeServerMaintenance:       "server maintenance",
+	CodeInvalidAccount:          "invalid account",
+	CodeLicenseError:            "license error",
+	CodeReadOnlyMode:            "read only mode",
+	CodeMaxStations:             "max stations",
+	CodeAccessDenied:            "access denied",
+	CodeNeedPlaylist:            "there is a need to specify the additional destination playlist",
+	CodeNeedNewPlaylist:         "there is a need to create a new playlist",
+	CodeSimultaneousLoginsLimit: "simultaneous logins has reached the upper limit",
+	CodeLinkingInProgress:       "linking in progress",
+	CodeUnlinkingInProgress:     "unlinking in progress",
+}
+
+func (c ResponseCode) String() string {
+	if text, ok := responseCodeText[c]; ok {
+		return text
+	}
+	return fmt.Sprintf("unknown response code %d", int(c))
+}
+
+func (c ResponseCode) Error() string {
+	return c.String()
+}
+
+// Error is returned if the device answers with a response code other than CodeOK
+type Error struct {
+	// Endpoint that was called, e.g. "main/setPower"
+	Endpoint string
+	Code     ResponseCode
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v (response code %d)", e.Endpoint, e.Code, int(e.Code))
+}
+
+// Unwrap returns the ResponseCode
+func (e *Error) Unwrap() error {
+	return e.Code
+}
@@ -0,0 +1,162 @@
+package musiccast
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// EventSubscriptionTimeout after which a device stops sending events if no
+// further request with event headers was made
+const EventSubscriptionTimeout = 10 * time.Minute
+
+// Event is sent by a device over UDP when its state changes.
+// Only the parts that changed are present.
+type Event struct {
+	DeviceID string       `json:"device_id"`
+	System   *SystemEvent `json:"system"`
+	Main     *ZoneEvent   `json:"main"`
+	Zone2    *ZoneEvent   `json:"zone2"`
+	Zone3    *ZoneEvent   `json:"zone3"`
+	Zone4    *ZoneEvent   `json:"zone4"`
+	Tuner    *TunerEvent  `json:"tuner"`
+	NetUSB   *NetUSBEvent `json:"netusb"`
+	CD       *CDEvent     `json:"cd"`
+	Dist     *DistEvent   `json:"dist"`
+}
+
+// SystemEvent contains changes of system wide state
+type SystemEvent struct {
+	FuncStatusUpdated     bool `json:"func_status_updated"`
+	NameTextUpdated       bool `json:"name_text_updated"`
+	LocationInfoUpdated   bool `json:"location_info_updated"`
+	StereoPairInfoUpdated bool `json:"stereo_pair_info_updated"`
+}
+
+// ZoneEvent contains changes of a zone. Power, Input, Volume and Mute are only
+// valid if set in the event, StatusUpdated signals other changes of the zone
+// status.
+type ZoneEvent struct {
+	Power             *string `json:"power"`
+	Input             *string `json:"input"`
+	Volume            *int    `json:"volume"`
+	Mute              *bool   `json:"mute"`
+	StatusUpdated     bool    `json:"status_updated"`
+	SignalInfoUpdated bool    `json:"signal_info_updated"`
+}
+
+// TunerEvent contains changes of the tuner
+type TunerEvent struct {
+	PlayInfoUpdated   bool `json:"play_info_updated"`
+	PresetInfoUpdated bool `json:"preset_info_updated"`
+}
+
+// NetUSBEvent contains changes of net/usb
+type NetUSBEvent struct {
+	PlayInfoUpdated   bool `json:"play_info_updated"`
+	PresetInfoUpdated bool `json:"preset_info_updated"`
+	RecentInfoUpdated bool `json:"recent_info_updated"`
+	ListInfoUpdated   bool `json:"list_info_updated"`
+	PlayTime          *int `json:"play_time"`
+	PlayError         int  `json:"play_error"`
+}
+
+// CDEvent contains changes of the CD player
+type CDEvent struct {
+	DeviceStatus    string `json:"device_status"`
+	PlayInfoUpdated bool   `json:"play_info_updated"`
+	PlayTime        *int   `json:"play_time"`
+}
+
+// DistEvent contains changes of the MusicCast Link state
+type DistEvent struct {
+	DistInfoUpdated bool `json:"dist_info_updated"`
+}
+
+// ParseEvent decodes the payload of an event datagram
+func ParseEvent(data []byte) (*Event, error) {
+	var event Event
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Zone returns the changes of the given zone or nil if it did not change
+func (e *Event) Zone(zone string) *ZoneEvent {
+	switch zone {
+	case ZoneMain:
+		return e.Main
+	case Zone2:
+		return e.Zone2
+	case Zone3:
+		return e.Zone3
+	case Zone4:
+		return e.Zone4
+	}
+	return nil
+}
+
+// EventListener receives events of all subscribed devices.
+// Devices are subscribed by every call of a client created with WithEvents.
+type EventListener struct {
+	conn *net.UDPConn
+}
+
+// ListenEvents opens an EventListener on the given local address,
+// e.g. ":41100". Use ":0" to pick a free port.
+func ListenEvents(address string) (*EventListener, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &EventListener{conn: conn}, nil
+}
+
+// Port the listener receives events on
+func (l *EventListener) Port() int {
+	return l.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// ReadEvent blocks until the next event arrives and returns it with the IP of the sending device
+func (l *EventListener) ReadEvent() (string, *Event, error) {
+	buffer := make([]byte, 65536)
+	for {
+		n, addr, err := l.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return "", nil, err
+		}
+
+		event, err := ParseEvent(buffer[:n])
+		if err != nil {
+			// ignore garbage and continue with next datagram
+			continue
+		}
+		return addr.IP.String(), event, nil
+	}
+}
+
+// Close the listener. Blocked ReadEvent calls return with an error.
+func (l *EventListener) Close() error {
+	return l.conn.Close()
+}
+
+// WithEvents returns a copy of the client that subscribes the device to send
+// events to port with every request. appName identifies the subscriber.
+// The subscription must be renewed by a request within EventSubscriptionTimeout.
+func (c *Client) WithEvents(appName string, port int) *Client {
+	client := *c
+	client.eventHeaders = map[string]string{
+		"X-AppName": fmt.Sprintf("MusicCast/1.0(%v)", appName),
+		"X-AppPort": strconv.Itoa(port),
+	}
+	return &client
+}
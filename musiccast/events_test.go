@@ -0,0 +1,138 @@
+package musiccast
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+		check   func(t *testing.T, event *Event)
+	}{
+		{
+			name: "zone power and volume",
+			data: `{"main":{"power":"on","volume":30},"device_id":"AC44F2000000"}`,
+			check: func(t *testing.T, event *Event) {
+				if event.DeviceID != "AC44F2000000" {
+					t.Errorf("DeviceID = %q", event.DeviceID)
+				}
+				if event.Main == nil || event.Main.Power == nil || *event.Main.Power != PowerOn {
+					t.Errorf("Main.Power = %+v, want on", event.Main)
+				}
+				if event.Main.Volume == nil || *event.Main.Volume != 30 {
+					t.Errorf("Main.Volume = %v, want 30", event.Main.Volume)
+				}
+				if event.Main.Mute != nil || event.Main.Input != nil {
+					t.Errorf("unchanged fields are set: %+v", event.Main)
+				}
+			},
+		},
+		{
+			name: "netusb play info",
+			data: `{"netusb":{"play_info_updated":true,"play_time":42}}`,
+			check: func(t *testing.T, event *Event) {
+				if event.NetUSB == nil || !event.NetUSB.PlayInfoUpdated {
+					t.Errorf("NetUSB = %+v, want play info updated", event.NetUSB)
+				}
+				if event.NetUSB.PlayTime == nil || *event.NetUSB.PlayTime != 42 {
+					t.Errorf("NetUSB.PlayTime = %v, want 42", event.NetUSB.PlayTime)
+				}
+				if event.Main != nil || event.System != nil {
+					t.Errorf("unchanged parts are set: %+v", event)
+				}
+			},
+		},
+		{
+			name: "system and dist",
+			data: `{"system":{"func_status_updated":true},"dist":{"dist_info_updated":true}}`,
+			check: func(t *testing.T, event *Event) {
+				if event.System == nil || !event.System.FuncStatusUpdated {
+					t.Errorf("System = %+v, want func status updated", event.System)
+				}
+				if event.Dist == nil || !event.Dist.DistInfoUpdated {
+					t.Errorf("Dist = %+v, want dist info updated", event.Dist)
+				}
+			},
+		},
+		{name: "garbage", data: `not json`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event, err := ParseEvent([]byte(test.data))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseEvent() error = %v, want error %v", err, test.wantErr)
+			}
+			if test.check != nil {
+				test.check(t, event)
+			}
+		})
+	}
+}
+
+func TestEventZone(t *testing.T) {
+	event, err := ParseEvent([]byte(`{"main":{"status_updated":true},"zone3":{"signal_info_updated":true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		zone string
+		want *ZoneEvent
+	}{
+		{zone: ZoneMain, want: event.Main},
+		{zone: Zone2, want: nil},
+		{zone: Zone3, want: event.Zone3},
+		{zone: Zone4, want: nil},
+		{zone: "unknown", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.zone, func(t *testing.T) {
+			if got := event.Zone(test.zone); got != test.want {
+				t.Errorf("Zone(%q) = %+v, want %+v", test.zone, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEventListener(t *testing.T) {
+	listener, err := ListenEvents("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	sendUDP(t, listener.Port(), "garbage")
+	sendUDP(t, listener.Port(), `{"main":{"mute":true}}`)
+
+	ip, event, err := listener.ReadEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip != "127.0.0.1" {
+		t.Errorf("ip = %q, want 127.0.0.1", ip)
+	}
+	if event.Main == nil || event.Main.Mute == nil || !*event.Main.Mute {
+		t.Errorf("Main = %+v, want mute", event.Main)
+	}
+}
+
+// sendUDP sends data to port on localhost
+func sendUDP(t *testing.T, port int, data string) {
+	t.Helper()
+
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
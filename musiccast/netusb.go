@@ -0,0 +1,244 @@
+package musiccast
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Playback commands for SetNetUSBPlayback and SetCDPlayback and values of PlayInfo.Playback
+const (
+	PlaybackPlay             = "play"
+	PlaybackStop             = "stop"
+	PlaybackPause            = "pause"
+	PlaybackPlayPause        = "play_pause"
+	PlaybackPrevious         = "previous"
+	PlaybackNext             = "next"
+	PlaybackFastReverseStart = "fast_reverse_start"
+	PlaybackFastReverseEnd   = "fast_reverse_end"
+	PlaybackFastForwardStart = "fast_forward_start"
+	PlaybackFastForwardEnd   = "fast_forward_end"
+)
+
+// Repeat modes of PlayInfo.Repeat
+const (
+	RepeatOff = "off"
+	RepeatOne = "one"
+	RepeatAll = "all"
+)
+
+// Shuffle modes of PlayInfo.Shuffle. Sources support either on or songs and albums.
+const (
+	ShuffleOff    = "off"
+	ShuffleOn     = "on"
+	ShuffleSongs  = "songs"
+	ShuffleAlbums = "albums"
+)
+
+// PlayInfo is the response of netusb/getPlayInfo
+type PlayInfo struct {
+	Response
+	Input            string   `json:"input"`
+	PlayQueueType    string   `json:"play_queue_type"`
+	Playback         string   `json:"playback"` // play, stop, pause, fast_reverse or fast_forward
+	Repeat           string   `json:"repeat"`   // see Repeat constants
+	Shuffle          string   `json:"shuffle"`  // see Shuffle constants
+	PlayTime         int      `json:"play_time"`
+	TotalTime        int      `json:"total_time"`
+	Artist           string   `json:"artist"`
+	Album            string   `json:"album"`
+	Track            string   `json:"track"`
+	AlbumartURL      string   `json:"albumart_url"`
+	AlbumartID       int      `json:"albumart_id"`
+	USBDeviceType    string   `json:"usb_devicetype"`
+	AutoStopped      bool     `json:"auto_stopped"`
+	Attribute        int      `json:"attribute"`
+	RepeatAvailable  []string `json:"repeat_available"`
+	ShuffleAvailable []string `json:"shuffle_available"`
+}
+
+// GetNetUSBPlayInfo returns what net/usb is currently playing
+func (c *Client) GetNetUSBPlayInfo(ctx context.Context) (*PlayInfo, error) {
+	var info PlayInfo
+	err := c.get(ctx, "netusb/getPlayInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetAlbumArt downloads the album art of PlayInfo.AlbumartURL.
+// Returns the image data and its content type.
+func (c *Client) GetAlbumArt(ctx context.Context, albumartURL string) ([]byte, string, error) {
+	imageURL := albumartURL
+	if !strings.HasPrefix(imageURL, "http://") && !strings.HasPrefix(imageURL, "https://") {
+		// relative to the device
+		imageURL = fmt.Sprintf("http://%v/%v", c.host, strings.TrimPrefix(imageURL, "/"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("Got wrong status code %v", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return data, contentType, nil
+}
+
+// SetNetUSBPlayback sends a playback command, see the Playback constants
+func (c *Client) SetNetUSBPlayback(ctx context.Context, playback string) error {
+	return c.get(ctx, "netusb/setPlayback", url.Values{"playback": {playback}}, nil)
+}
+
+// SetNetUSBPlayPosition seeks to position in seconds
+func (c *Client) SetNetUSBPlayPosition(ctx context.Context, position int) error {
+	return c.get(ctx, "netusb/setPlayPosition", url.Values{"position": {strconv.Itoa(position)}}, nil)
+}
+
+// ToggleNetUSBRepeat cycles the repeat mode
+func (c *Client) ToggleNetUSBRepeat(ctx context.Context) error {
+	return c.get(ctx, "netusb/toggleRepeat", nil, nil)
+}
+
+// ToggleNetUSBShuffle cycles the shuffle mode
+func (c *Client) ToggleNetUSBShuffle(ctx context.Context) error {
+	return c.get(ctx, "netusb/toggleShuffle", nil, nil)
+}
+
+// PresetInfo is the response of netusb/getPresetInfo
+type PresetInfo struct {
+	Response
+	PresetInfo []NetUSBPreset `json:"preset_info"`
+	FuncList   []string       `json:"func_list"`
+}
+
+// NetUSBPreset is a single net/usb favourite. Presets are numbered
+// starting with 1 in the order of PresetInfo.PresetInfo.
+type NetUSBPreset struct {
+	Input     string `json:"input"` // PresetInputUnknown for empty presets
+	Text      string `json:"text"`
+	Attribute int    `json:"attribute"`
+}
+
+// PresetInputUnknown is the input of empty presets
+const PresetInputUnknown = "unknown"
+
+// GetNetUSBPresetInfo returns the stored net/usb presets
+func (c *Client) GetNetUSBPresetInfo(ctx context.Context) (*PresetInfo, error) {
+	var info PresetInfo
+	err := c.get(ctx, "netusb/getPresetInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// RecallNetUSBPreset plays the preset with the given number in the zone of the client
+func (c *Client) RecallNetUSBPreset(ctx context.Context, num int) error {
+	return c.get(ctx, "netusb/recallPreset", url.Values{"zone": {c.zone}, "num": {strconv.Itoa(num)}}, nil)
+}
+
+// StoreNetUSBPreset stores the current content as preset with the given number
+func (c *Client) StoreNetUSBPreset(ctx context.Context, num int) error {
+	return c.get(ctx, "netusb/storePreset", url.Values{"num": {strconv.Itoa(num)}}, nil)
+}
+
+// RecentInfo is the response of netusb/getRecentInfo
+type RecentInfo struct {
+	Response
+	RecentInfo []RecentItem `json:"recent_info"`
+}
+
+// RecentItem is a recently played content
+type RecentItem struct {
+	Input       string `json:"input"`
+	Text        string `json:"text"`
+	AlbumartURL string `json:"albumart_url"`
+	PlayCount   int    `json:"play_count"`
+	Attribute   int    `json:"attribute"`
+}
+
+// GetNetUSBRecentInfo returns the recently played content
+func (c *Client) GetNetUSBRecentInfo(ctx context.Context) (*RecentInfo, error) {
+	var info RecentInfo
+	err := c.get(ctx, "netusb/getRecentInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// RecallNetUSBRecentItem plays the recent item with the given number in the zone of the client
+func (c *Client) RecallNetUSBRecentItem(ctx context.Context, num int) error {
+	return c.get(ctx, "netusb/recallRecentItem", url.Values{"zone": {c.zone}, "num": {strconv.Itoa(num)}}, nil)
+}
+
+// ListInfo is the response of netusb/getListInfo
+type ListInfo struct {
+	Response
+	Input        string     `json:"input"`
+	MenuLayer    int        `json:"menu_layer"`
+	MaxLine      int        `json:"max_line"`
+	Index        int        `json:"index"`
+	PlayingIndex int        `json:"playing_index"`
+	MenuName     string     `json:"menu_name"`
+	ListInfo     []ListItem `json:"list_info"`
+}
+
+// ListItem is a single entry of a list
+type ListItem struct {
+	Text      string `json:"text"`
+	Thumbnail string `json:"thumbnail"`
+	Attribute int    `json:"attribute"`
+}
+
+// GetNetUSBListInfo returns size entries of the list of input starting at index
+func (c *Client) GetNetUSBListInfo(ctx context.Context, input string, index int, size int) (*ListInfo, error) {
+	query := url.Values{
+		"input": {input},
+		"index": {strconv.Itoa(index)},
+		"size":  {strconv.Itoa(size)},
+	}
+
+	var info ListInfo
+	err := c.get(ctx, "netusb/getListInfo", query, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SetNetUSBListControl selects ("select"), plays ("play") or leaves ("return")
+// the list entry at index in the zone of the client
+func (c *Client) SetNetUSBListControl(ctx context.Context, controlType string, index int) error {
+	query := url.Values{
+		"list_id": {"main"},
+		"type":    {controlType},
+		"zone":    {c.zone},
+	}
+	if controlType != "return" {
+		query.Set("index", strconv.Itoa(index))
+	}
+	return c.get(ctx, "netusb/setListControl", query, nil)
+}
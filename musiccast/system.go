@@ -0,0 +1,327 @@
+package musiccast
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// DeviceInfo is the response of system/getDeviceInfo
+type DeviceInfo struct {
+	Response
+	ModelName         string  `json:"model_name"`
+	Destination       string  `json:"destination"`
+	DeviceID          string  `json:"device_id"`
+	SystemID          string  `json:"system_id"`
+	SystemVersion     float64 `json:"system_version"`
+	APIVersion        float64 `json:"api_version"`
+	NetmoduleVersion  string  `json:"netmodule_version"`
+	NetmoduleChecksum string  `json:"netmodule_checksum"`
+	OperationMode     string  `json:"operation_mode"`
+	UpdateErrorCode   string  `json:"update_error_code"`
+}
+
+// GetDeviceInfo returns basic information like model name and device id
+func (c *Client) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
+	var info DeviceInfo
+	err := c.get(ctx, "system/getDeviceInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Features is the response of system/getFeatures
+type Features struct {
+	Response
+	System       SystemFeatures       `json:"system"`
+	Zone         []ZoneFeatures       `json:"zone"`
+	Tuner        TunerFeatures        `json:"tuner"`
+	NetUSB       NetUSBFeatures       `json:"netusb"`
+	Distribution DistributionFeatures `json:"distribution"`
+}
+
+// SystemFeatures of the device
+type SystemFeatures struct {
+	FuncList  []string       `json:"func_list"`
+	ZoneNum   int            `json:"zone_num"`
+	InputList []InputFeature `json:"input_list"`
+}
+
+// InputFeature describes an input of the device
+type InputFeature struct {
+	ID                 string `json:"id"`
+	DistributionEnable bool   `json:"distribution_enable"`
+	RenameEnable       bool   `json:"rename_enable"`
+	AccountEnable      bool   `json:"account_enable"`
+	PlayInfoType       string `json:"play_info_type"` // see PlayInfoType constants
+}
+
+// Play info types of inputs
+const (
+	PlayInfoTypeNone   = "none"
+	PlayInfoTypeTuner  = "tuner"
+	PlayInfoTypeNetUSB = "netusb"
+	PlayInfoTypeCD     = "cd"
+)
+
+// ZoneFeatures describes the capabilities of a single zone
+type ZoneFeatures struct {
+	ID                  string      `json:"id"`
+	FuncList            []string    `json:"func_list"`
+	InputList           []string    `json:"input_list"`
+	SoundProgramList    []string    `json:"sound_program_list"`
+	SurrDecoderTypeList []string    `json:"surr_decoder_type_list"`
+	ToneControlModeList []string    `json:"tone_control_mode_list"`
+	LinkControlList     []string    `json:"link_control_list"`
+	LinkAudioDelayList  []string    `json:"link_audio_delay_list"`
+	RangeStep           []RangeStep `json:"range_step"`
+	SceneNum            int         `json:"scene_num"`
+	CursorList          []string    `json:"cursor_list"`
+	MenuList            []string    `json:"menu_list"`
+}
+
+// RangeStep describes the valid values of a numeric parameter
+type RangeStep struct {
+	ID   string  `json:"id"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step"`
+}
+
+// TunerFeatures of the device
+type TunerFeatures struct {
+	FuncList  []string    `json:"func_list"`
+	RangeStep []RangeStep `json:"range_step"`
+	Preset    struct {
+		Type string `json:"type"` // common or separate
+		Num  int    `json:"num"`
+	} `json:"preset"`
+}
+
+// NetUSBFeatures of the device
+type NetUSBFeatures struct {
+	FuncList []string `json:"func_list"`
+	Preset   struct {
+		Num int `json:"num"`
+	} `json:"preset"`
+	RecentInfo struct {
+		Num int `json:"num"`
+	} `json:"recent_info"`
+	PlayQueue struct {
+		Size int `json:"size"`
+	} `json:"play_queue"`
+	NetRadioType string `json:"net_radio_type"`
+}
+
+// DistributionFeatures of the device
+type DistributionFeatures struct {
+	Version          float64  `json:"version"`
+	CompatibleClient []int    `json:"compatible_client"`
+	ClientMax        int      `json:"client_max"`
+	ServerZoneList   []string `json:"server_zone_list"`
+}
+
+// GetFeatures returns the capabilities of the device
+func (c *Client) GetFeatures(ctx context.Context) (*Features, error) {
+	var features Features
+	err := c.get(ctx, "system/getFeatures", nil, &features)
+	if err != nil {
+		return nil, err
+	}
+	return &features, nil
+}
+
+// ZoneFeatures returns the features of the zone with the given id or nil
+func (f *Features) ZoneFeatures(zone string) *ZoneFeatures {
+	for i := range f.Zone {
+		if f.Zone[i].ID == zone {
+			return &f.Zone[i]
+		}
+	}
+	return nil
+}
+
+// HasFunc reports if the zone supports the function, e.g. "pure_direct"
+func (z *ZoneFeatures) HasFunc(function string) bool {
+	return Contains(z.FuncList, function)
+}
+
+// RangeStepFor returns the range of the parameter with the given id or nil
+func (z *ZoneFeatures) RangeStepFor(id string) *RangeStep {
+	return findRangeStep(z.RangeStep, id)
+}
+
+// InputIDs returns the ids of all inputs of the device
+func (s *SystemFeatures) InputIDs() []string {
+	ids := make([]string, 0, len(s.InputList))
+	for _, input := range s.InputList {
+		ids = append(ids, input.ID)
+	}
+	return ids
+}
+
+// PlayInfoType returns the play info type of the input, see PlayInfoType constants
+func (s *SystemFeatures) PlayInfoType(input string) string {
+	for _, feature := range s.InputList {
+		if feature.ID == input {
+			return feature.PlayInfoType
+		}
+	}
+	return PlayInfoTypeNone
+}
+
+// HasFunc reports if the system supports the function, e.g. "party_mode"
+func (s *SystemFeatures) HasFunc(function string) bool {
+	return Contains(s.FuncList, function)
+}
+
+// HasFunc reports if the tuner supports the function, e.g. "dab"
+func (t *TunerFeatures) HasFunc(function string) bool {
+	return Contains(t.FuncList, function)
+}
+
+// RangeStepFor returns the range of the parameter with the given id or nil
+func (t *TunerFeatures) RangeStepFor(id string) *RangeStep {
+	return findRangeStep(t.RangeStep, id)
+}
+
+// HasFunc reports if net/usb supports the function, e.g. "recall_preset"
+func (n *NetUSBFeatures) HasFunc(function string) bool {
+	return Contains(n.FuncList, function)
+}
+
+// Contains reports if the list contains the value, e.g. a function of a FuncList
+func Contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func findRangeStep(list []RangeStep, id string) *RangeStep {
+	for i := range list {
+		if list[i].ID == id {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// NetworkStatus is the response of system/getNetworkStatus
+type NetworkStatus struct {
+	Response
+	NetworkName    string `json:"network_name"`
+	Connection     string `json:"connection"`
+	DHCP           bool   `json:"dhcp"`
+	IPAddress      string `json:"ip_address"`
+	SubnetMask     string `json:"subnet_mask"`
+	DefaultGateway string `json:"default_gateway"`
+	DNSServer1     string `json:"dns_server_1"`
+	DNSServer2     string `json:"dns_server_2"`
+	MACAddress     struct {
+		Wired          string `json:"wired"`
+		Wireless       string `json:"wireless"`
+		WirelessDirect string `json:"wireless_direct"`
+	} `json:"mac_address"`
+}
+
+// GetNetworkStatus returns the network configuration of the device
+func (c *Client) GetNetworkStatus(ctx context.Context) (*NetworkStatus, error) {
+	var status NetworkStatus
+	err := c.get(ctx, "system/getNetworkStatus", nil, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// FuncStatus is the response of system/getFuncStatus
+type FuncStatus struct {
+	Response
+	AutoPowerStandby bool `json:"auto_power_standby"`
+	IRSensor         bool `json:"ir_sensor"`
+	SpeakerA         bool `json:"speaker_a"`
+	SpeakerB         bool `json:"speaker_b"`
+	Headphone        bool `json:"headphone"`
+	Dimmer           int  `json:"dimmer"`
+	HDMIOut1         bool `json:"hdmi_out_1"`
+	HDMIOut2         bool `json:"hdmi_out_2"`
+	PartyMode        bool `json:"party_mode"`
+}
+
+// GetFuncStatus returns the state of system wide functions
+func (c *Client) GetFuncStatus(ctx context.Context) (*FuncStatus, error) {
+	var status FuncStatus
+	err := c.get(ctx, "system/getFuncStatus", nil, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetAutoPowerStandby enables or disables auto power standby
+func (c *Client) SetAutoPowerStandby(ctx context.Context, enable bool) error {
+	return c.get(ctx, "system/setAutoPowerStandby", url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetPartyMode enables or disables party mode, which plays the main zone source in all zones.
+// Only available if SystemFeatures has the function "party_mode".
+func (c *Client) SetPartyMode(ctx context.Context, enable bool) error {
+	return c.get(ctx, "system/setPartyMode", url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// LocationInfo is the response of system/getLocationInfo
+type LocationInfo struct {
+	Response
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	ZoneList map[string]bool `json:"zone_list"`
+}
+
+// GetLocationInfo returns the MusicCast location the device belongs to
+func (c *Client) GetLocationInfo(ctx context.Context) (*LocationInfo, error) {
+	var info LocationInfo
+	err := c.get(ctx, "system/getLocationInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// NameText is the response of system/getNameText
+type NameText struct {
+	Response
+	ZoneList         []IDText `json:"zone_list"`
+	InputList        []IDText `json:"input_list"`
+	SoundProgramList []IDText `json:"sound_program_list"`
+}
+
+// IDText maps an id to its display text
+type IDText struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// GetNameText returns display names of zones, inputs and sound programs.
+// An empty id returns all names.
+func (c *Client) GetNameText(ctx context.Context, id string) (*NameText, error) {
+	var query url.Values
+	if id != "" {
+		query = url.Values{"id": {id}}
+	}
+
+	var nameText NameText
+	err := c.get(ctx, "system/getNameText", query, &nameText)
+	if err != nil {
+		return nil, err
+	}
+	return &nameText, nil
+}
+
+// SendIRCode sends an IR code in 8 digit hex
+func (c *Client) SendIRCode(ctx context.Context, code string) error {
+	return c.get(ctx, "system/sendIrCode", url.Values{"code": {code}}, nil)
+}
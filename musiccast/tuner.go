@@ -0,0 +1,152 @@
+package musiccast
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Tuner bands
+const (
+	BandCommon = "common"
+	BandAM     = "am"
+	BandFM     = "fm"
+	BandDAB    = "dab"
+)
+
+// Tuning modes for SetTunerFreq
+const (
+	TuningUp       = "up"
+	TuningDown     = "down"
+	TuningCancel   = "cancel"
+	TuningAutoUp   = "auto_up"
+	TuningAutoDown = "auto_down"
+	TuningTPUp     = "tp_up"
+	TuningTPDown   = "tp_down"
+	TuningDirect   = "direct"
+)
+
+// TunerPlayInfo is the response of tuner/getPlayInfo
+type TunerPlayInfo struct {
+	Response
+	Band       string      `json:"band"`
+	AutoScan   bool        `json:"auto_scan"`
+	AutoPreset bool        `json:"auto_preset"`
+	AM         TunerBand   `json:"am"`
+	FM         TunerBand   `json:"fm"`
+	RDS        RDS         `json:"rds"`
+	DAB        DABPlayInfo `json:"dab"`
+}
+
+// TunerBand is the state of the AM or FM band.
+// Freq is in kHz.
+type TunerBand struct {
+	Preset    int    `json:"preset"`
+	Freq      int    `json:"freq"`
+	Tuned     bool   `json:"tuned"`
+	AudioMode string `json:"audio_mode"`
+}
+
+// RDS information of the current FM station
+type RDS struct {
+	ProgramType    string `json:"program_type"`
+	ProgramService string `json:"program_service"`
+	RadioTextA     string `json:"radio_text_a"`
+	RadioTextB     string `json:"radio_text_b"`
+}
+
+// DABPlayInfo is the state of the DAB band
+type DABPlayInfo struct {
+	Preset        int    `json:"preset"`
+	ID            int    `json:"id"`
+	Status        string `json:"status"`
+	Freq          int    `json:"freq"`
+	Category      string `json:"category"`
+	AudioMode     string `json:"audio_mode"`
+	BitRate       int    `json:"bit_rate"`
+	Quality       int    `json:"quality"`
+	TuneAid       int    `json:"tune_aid"`
+	OffAir        bool   `json:"off_air"`
+	DABPlus       bool   `json:"dab_plus"`
+	ProgramType   string `json:"program_type"`
+	ChLabel       string `json:"ch_label"`
+	ServiceLabel  string `json:"service_label"`
+	DLS           string `json:"dls"`
+	EnsembleLabel string `json:"ensemble_label"`
+}
+
+// GetTunerPlayInfo returns the state of the tuner
+func (c *Client) GetTunerPlayInfo(ctx context.Context) (*TunerPlayInfo, error) {
+	var info TunerPlayInfo
+	err := c.get(ctx, "tuner/getPlayInfo", nil, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// TunerPresetInfo is the response of tuner/getPresetInfo
+type TunerPresetInfo struct {
+	Response
+	PresetInfo []TunerPreset `json:"preset_info"`
+	FuncList   []string      `json:"func_list"`
+}
+
+// TunerPreset is a single stored station. Presets are numbered
+// starting with 1 in the order of TunerPresetInfo.PresetInfo.
+type TunerPreset struct {
+	Band   string `json:"band"`
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// GetTunerPresetInfo returns the presets of band. Use BandCommon for devices
+// with common presets.
+func (c *Client) GetTunerPresetInfo(ctx context.Context, band string) (*TunerPresetInfo, error) {
+	var info TunerPresetInfo
+	err := c.get(ctx, "tuner/getPresetInfo", url.Values{"band": {band}}, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SetTunerBand switches the tuner to BandAM, BandFM or BandDAB
+func (c *Client) SetTunerBand(ctx context.Context, band string) error {
+	return c.get(ctx, "tuner/setBand", url.Values{"band": {band}}, nil)
+}
+
+// SetTunerFreq tunes band with the given tuning mode. freq in kHz is only
+// used with TuningDirect.
+func (c *Client) SetTunerFreq(ctx context.Context, band string, tuning string, freq int) error {
+	query := url.Values{"band": {band}, "tuning": {tuning}}
+	if tuning == TuningDirect {
+		query.Set("num", strconv.Itoa(freq))
+	}
+	return c.get(ctx, "tuner/setFreq", query, nil)
+}
+
+// RecallTunerPreset plays the preset with the given number of band in the zone of the client
+func (c *Client) RecallTunerPreset(ctx context.Context, band string, num int) error {
+	query := url.Values{
+		"zone": {c.zone},
+		"band": {band},
+		"num":  {strconv.Itoa(num)},
+	}
+	return c.get(ctx, "tuner/recallPreset", query, nil)
+}
+
+// SwitchTunerPreset selects the preset in DirectionNext or DirectionPrevious
+func (c *Client) SwitchTunerPreset(ctx context.Context, dir string) error {
+	return c.get(ctx, "tuner/switchPreset", url.Values{"dir": {dir}}, nil)
+}
+
+// StoreTunerPreset stores the current station as preset with the given number
+func (c *Client) StoreTunerPreset(ctx context.Context, num int) error {
+	return c.get(ctx, "tuner/storePreset", url.Values{"num": {strconv.Itoa(num)}}, nil)
+}
+
+// SetDABService selects the DAB service in DirectionNext or DirectionPrevious
+func (c *Client) SetDABService(ctx context.Context, dir string) error {
+	return c.get(ctx, "tuner/setDabService", url.Values{"dir": {dir}}, nil)
+}
@@ -0,0 +1,215 @@
+package musiccast
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Power values for SetPower and Status.Power
+const (
+	PowerOn      = "on"
+	PowerStandby = "standby"
+	PowerToggle  = "toggle"
+)
+
+// Directions for relative changes like SetVolumeStep, SwitchTunerPreset and SetDABService
+const (
+	DirectionUp       = "up"
+	DirectionDown     = "down"
+	DirectionNext     = "next"
+	DirectionPrevious = "previous"
+)
+
+// Status is the response of {zone}/getStatus
+type Status struct {
+	Response
+	Power              string      `json:"power"` // on or standby
+	Sleep              int         `json:"sleep"` // 0, 30, 60, 90 or 120 minutes
+	Volume             int         `json:"volume"`
+	Mute               bool        `json:"mute"`
+	MaxVolume          int         `json:"max_volume"`
+	Input              string      `json:"input"`
+	DistributionEnable bool        `json:"distribution_enable"`
+	SoundProgram       string      `json:"sound_program"`
+	SurrDecoderType    string      `json:"surr_decoder_type"`
+	PureDirect         bool        `json:"pure_direct"`
+	Enhancer           bool        `json:"enhancer"`
+	ToneControl        ToneControl `json:"tone_control"`
+	DialogueLevel      int         `json:"dialogue_level"`
+	DialogueLift       int         `json:"dialogue_lift"`
+	ClearVoice         bool        `json:"clear_voice"`
+	SubwooferVolume    int         `json:"subwoofer_volume"`
+	BassExtension      bool        `json:"bass_extension"`
+	ExtraBass          bool        `json:"extra_bass"`
+	LinkControl        string      `json:"link_control"`
+	LinkAudioDelay     string      `json:"link_audio_delay"`
+	DisableFlags       int         `json:"disable_flags"`
+	PartyEnable        bool        `json:"party_enable"`
+	Direct             bool        `json:"direct"`
+}
+
+// ToneControl settings of a zone
+type ToneControl struct {
+	Mode   string `json:"mode"`
+	Bass   int    `json:"bass"`
+	Treble int    `json:"treble"`
+}
+
+// IsOn reports if the zone is powered on
+func (s *Status) IsOn() bool {
+	return s.Power == PowerOn
+}
+
+// GetStatus returns the state of the zone
+func (c *Client) GetStatus(ctx context.Context) (*Status, error) {
+	var status Status
+	err := c.get(ctx, c.zonePath("getStatus"), nil, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SoundProgramList is the response of {zone}/getSoundProgramList
+type SoundProgramList struct {
+	Response
+	SoundProgramList []string `json:"sound_program_list"`
+}
+
+// GetSoundProgramList returns the sound programs available in the zone
+func (c *Client) GetSoundProgramList(ctx context.Context) ([]string, error) {
+	var list SoundProgramList
+	err := c.get(ctx, c.zonePath("getSoundProgramList"), nil, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.SoundProgramList, nil
+}
+
+// SetPower of the zone to PowerOn, PowerStandby or PowerToggle
+func (c *Client) SetPower(ctx context.Context, power string) error {
+	return c.get(ctx, c.zonePath("setPower"), url.Values{"power": {power}}, nil)
+}
+
+// SetSleep sets the sleep timer to 0, 30, 60, 90 or 120 minutes
+func (c *Client) SetSleep(ctx context.Context, minutes int) error {
+	return c.get(ctx, c.zonePath("setSleep"), url.Values{"sleep": {strconv.Itoa(minutes)}}, nil)
+}
+
+// SetVolume to an absolute value
+func (c *Client) SetVolume(ctx context.Context, volume int) error {
+	return c.get(ctx, c.zonePath("setVolume"), url.Values{"volume": {strconv.Itoa(volume)}}, nil)
+}
+
+// SetVolumeStep changes the volume by step in DirectionUp or DirectionDown.
+// A step of 0 uses the default step of the device.
+func (c *Client) SetVolumeStep(ctx context.Context, direction string, step int) error {
+	query := url.Values{"volume": {direction}}
+	if step > 0 {
+		query.Set("step", strconv.Itoa(step))
+	}
+	return c.get(ctx, c.zonePath("setVolume"), query, nil)
+}
+
+// SetMute enables or disables mute
+func (c *Client) SetMute(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setMute"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetInput selects the input of the zone, e.g. "hdmi1" or "net_radio"
+func (c *Client) SetInput(ctx context.Context, input string) error {
+	return c.get(ctx, c.zonePath("setInput"), url.Values{"input": {input}}, nil)
+}
+
+// PrepareInputChange lets the device prepare for an upcoming SetInput
+func (c *Client) PrepareInputChange(ctx context.Context, input string) error {
+	return c.get(ctx, c.zonePath("prepareInputChange"), url.Values{"input": {input}}, nil)
+}
+
+// SetSoundProgram selects the sound program, e.g. "straight" or "movie"
+func (c *Client) SetSoundProgram(ctx context.Context, program string) error {
+	return c.get(ctx, c.zonePath("setSoundProgram"), url.Values{"program": {program}}, nil)
+}
+
+// SetSurroundDecoderType selects the surround decoder, e.g. "dolby_pl2x_movie"
+func (c *Client) SetSurroundDecoderType(ctx context.Context, decoderType string) error {
+	return c.get(ctx, c.zonePath("setSurroundDecoderType"), url.Values{"type": {decoderType}}, nil)
+}
+
+// Set3DSurround enables or disables 3D surround
+func (c *Client) Set3DSurround(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("set3dSurround"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetDirect enables or disables direct
+func (c *Client) SetDirect(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setDirect"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetPureDirect enables or disables pure direct
+func (c *Client) SetPureDirect(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setPureDirect"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetEnhancer enables or disables the enhancer
+func (c *Client) SetEnhancer(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setEnhancer"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetToneControl sets mode, bass and treble
+func (c *Client) SetToneControl(ctx context.Context, toneControl ToneControl) error {
+	query := url.Values{
+		"bass":   {strconv.Itoa(toneControl.Bass)},
+		"treble": {strconv.Itoa(toneControl.Treble)},
+	}
+	if toneControl.Mode != "" {
+		query.Set("mode", toneControl.Mode)
+	}
+	return c.get(ctx, c.zonePath("setToneControl"), query, nil)
+}
+
+// SetDialogueLevel sets the dialogue level
+func (c *Client) SetDialogueLevel(ctx context.Context, value int) error {
+	return c.get(ctx, c.zonePath("setDialogueLevel"), url.Values{"value": {strconv.Itoa(value)}}, nil)
+}
+
+// SetDialogueLift sets the dialogue lift
+func (c *Client) SetDialogueLift(ctx context.Context, value int) error {
+	return c.get(ctx, c.zonePath("setDialogueLift"), url.Values{"value": {strconv.Itoa(value)}}, nil)
+}
+
+// SetClearVoice enables or disables clear voice
+func (c *Client) SetClearVoice(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setClearVoice"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetSubwooferVolume sets the subwoofer volume
+func (c *Client) SetSubwooferVolume(ctx context.Context, volume int) error {
+	return c.get(ctx, c.zonePath("setSubwooferVolume"), url.Values{"volume": {strconv.Itoa(volume)}}, nil)
+}
+
+// SetBassExtension enables or disables bass extension
+func (c *Client) SetBassExtension(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setBassExtension"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// SetExtraBass enables or disables extra bass
+func (c *Client) SetExtraBass(ctx context.Context, enable bool) error {
+	return c.get(ctx, c.zonePath("setExtraBass"), url.Values{"enable": {strconv.FormatBool(enable)}}, nil)
+}
+
+// RecallScene recalls the scene with the given number, usually 1 to 8
+func (c *Client) RecallScene(ctx context.Context, num int) error {
+	return c.get(ctx, c.zonePath("recallScene"), url.Values{"num": {strconv.Itoa(num)}}, nil)
+}
+
+// SetLinkControl sets the link control, e.g. "standard" or "stability"
+func (c *Client) SetLinkControl(ctx context.Context, control string) error {
+	return c.get(ctx, c.zonePath("setLinkControl"), url.Values{"control": {control}}, nil)
+}
+
+// SetLinkAudioDelay sets the link audio delay, e.g. "audio_sync" or "lip_sync"
+func (c *Client) SetLinkAudioDelay(ctx context.Context, delay string) error {
+	return c.get(ctx, c.zonePath("setLinkAudioDelay"), url.Values{"delay": {delay}}, nil)
+}
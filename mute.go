@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// muteKey toggles mute of a device
+type muteKey struct {
+	handler *musicCastHandler
+}
+
+func (a muteKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a muteKey) render(sender sdplugin.Sender, context string, _ actionContext, state deviceState) error {
+	return sender.SetState(context, muteState(state.status))
+}
+
+// keyDown toggles mute of the device
+func (a muteKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	err = device.SetMute(context.Background(), !status.Mute)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err = device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	log.Printf("Is MusicCast device muted? %v", status.Mute)
+
+	setStateDelayed(sender, sdContext, muteState(status))
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return nil
+}
+
+// muteState returns the streamdeck state for the mute status of the device
+func muteState(status *musiccast.Status) int {
+	if status.Mute {
+		return 1 // muted
+	}
+	return 0 // unmuted
+}
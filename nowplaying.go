@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// nowPlayingKey shows the playing track and toggles between play and pause
+type nowPlayingKey struct {
+	handler *musicCastHandler
+}
+
+func (a nowPlayingKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a nowPlayingKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	playback, ok := state.playback()
+	if !ok {
+		return nil
+	}
+
+	playInfo := state.playInfo
+	if playInfo == nil || !state.status.IsOn() || state.status.Input == cdInput || playback == musiccast.PlaybackStop {
+		// the zone is off or plays another input, show the default icon
+		playInfo = &musiccast.PlayInfo{Playback: musiccast.PlaybackStop}
+	}
+	return a.show(sender, context, actionContext.settings, playInfo)
+}
+
+// keyDown toggles between play and pause
+func (a nowPlayingKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	err := a.handler.device(settings).SetNetUSBPlayback(context.Background(), musiccast.PlaybackPlayPause)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	a.handler.refreshDevice(settings.deviceKey())
+	return nil
+}
+
+// show renders the album art of the current net/usb content with its track as title
+func (a nowPlayingKey) show(sender sdplugin.Sender, sdContext string, settings Settings, playInfo *musiccast.PlayInfo) error {
+	title := playInfo.Track
+	if playInfo.Playback == musiccast.PlaybackStop {
+		title = ""
+	}
+	err := sender.SetTitle(sdContext, title, sdplugin.TargetBoth)
+	if err != nil {
+		return err
+	}
+
+	// album art only changes with the track, avoid downloading it with every update
+	albumArtURL := playInfo.AlbumartURL
+	a.handler.albumArtMapMutex.Lock()
+	shownURL, ok := a.handler.albumArtMap[sdContext]
+	a.handler.albumArtMap[sdContext] = albumArtURL
+	a.handler.albumArtMapMutex.Unlock()
+	if ok && shownURL == albumArtURL {
+		return nil
+	}
+
+	if albumArtURL == "" {
+		// an empty image restores the default icon
+		return sender.SetImage(sdContext, "", sdplugin.TargetBoth)
+	}
+
+	// downloading may take seconds, do not hold up the updates of other keys
+	go a.showAlbumArt(sender, sdContext, settings, albumArtURL)
+	return nil
+}
+
+// showAlbumArt downloads the album art and sets it as image unless the track changed in the meantime
+func (a nowPlayingKey) showAlbumArt(sender sdplugin.Sender, sdContext string, settings Settings, albumArtURL string) {
+	// an empty image restores the default icon
+	image := ""
+	data, contentType, err := a.handler.device(settings).GetAlbumArt(context.Background(), albumArtURL)
+	if err != nil {
+		log.Printf("Could not fetch album art: %v\n", err)
+	} else {
+		image = "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	}
+
+	a.handler.albumArtMapMutex.Lock()
+	current, ok := a.handler.albumArtMap[sdContext]
+	a.handler.albumArtMapMutex.Unlock()
+	if !ok || current != albumArtURL {
+		// track changed or key disappeared
+		return
+	}
+
+	err = sender.SetImage(sdContext, image, sdplugin.TargetBoth)
+	if err != nil {
+		log.Printf("Failed to show album art: %v\n", err)
+	}
+}
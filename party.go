@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// partyModeFunc is the id of party mode in the func_list of the system features
+const partyModeFunc = "party_mode"
+
+// partyKey toggles party mode
+type partyKey struct {
+	handler *musicCastHandler
+}
+
+func (a partyKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a partyKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	if state.distInfo == nil && state.funcStatus == nil {
+		return nil
+	}
+	return sender.SetState(context, a.state(actionContext.settings, state))
+}
+
+// keyDown toggles party mode of the configured device. Devices without party mode
+// link all known devices to their configured zone instead.
+func (a partyKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	features, err := a.handler.features(settings.deviceKey().ip)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	key := settings.deviceKey()
+	device := a.handler.deviceFor(key)
+	state := deviceState{}
+	if features.System.HasFunc(partyModeFunc) {
+		funcStatus, err := device.GetFuncStatus(context.Background())
+		if err != nil {
+			sender.ShowAlert(sdContext)
+			return err
+		}
+
+		err = device.SetPartyMode(context.Background(), !funcStatus.PartyMode)
+		if err != nil {
+			sender.ShowAlert(sdContext)
+			return err
+		}
+
+		state.funcStatus, err = device.GetFuncStatus(context.Background())
+		if err != nil {
+			return err
+		}
+	} else {
+		clients := a.handler.partyClients(settings.deviceKey().ip)
+		if len(clients) == 0 {
+			sender.ShowAlert(sdContext)
+			return errors.New("No other devices known")
+		}
+
+		distInfo, err := device.GetDistributionInfo(context.Background())
+		if err != nil {
+			sender.ShowAlert(sdContext)
+			return err
+		}
+
+		if isLinked(distInfo, clients) {
+			err = a.handler.unlinkDevices(key, distInfo)
+		} else {
+			if distInfo.Role == musiccast.RoleServer {
+				// replace a smaller group
+				err = a.handler.unlinkDevices(key, distInfo)
+				if err != nil {
+					log.Printf("Could not dissolve group: %v\n", err)
+				}
+			}
+			err = a.handler.linkDevices(key, clients)
+		}
+		if err != nil {
+			sender.ShowAlert(sdContext)
+			return err
+		}
+
+		state.distInfo, err = device.GetDistributionInfo(context.Background())
+		if err != nil {
+			return err
+		}
+	}
+
+	setStateDelayed(sender, sdContext, a.state(settings, state))
+	a.handler.deviceChanged(sender, key, state, sdContext)
+	return nil
+}
+
+// partyClients returns the IPs of all known devices except the master
+func (m *musicCastHandler) partyClients(master string) []string {
+	clients := []string{}
+	for _, device := range m.knownDevices() {
+		if device.Host != master {
+			clients = append(clients, device.Host)
+		}
+	}
+	return clients
+}
+
+// state returns the streamdeck state for party mode, or for the group
+// of all known devices if the device has no party mode
+func (a partyKey) state(settings Settings, state deviceState) int {
+	features, err := a.handler.features(settings.deviceKey().ip)
+	if err != nil {
+		return 1 // off
+	}
+
+	if features.System.HasFunc(partyModeFunc) {
+		if state.funcStatus != nil && state.funcStatus.PartyMode {
+			return 0 // on
+		}
+		return 1 // off
+	}
+
+	if state.distInfo != nil && isLinked(state.distInfo, a.handler.partyClients(settings.deviceKey().ip)) {
+		return 0 // on
+	}
+	return 1 // off
+}
+
+// needsFuncStatus reports if the action shows system function state
+func needsFuncStatus(action string) bool {
+	return action == partyAction
+}
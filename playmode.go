@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// playModeKey cycles the shuffle or repeat mode depending on its action
+type playModeKey struct {
+	handler *musicCastHandler
+	action  string
+}
+
+func (a playModeKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a playModeKey) render(sender sdplugin.Sender, context string, _ actionContext, state deviceState) error {
+	if state.playInfo == nil {
+		return nil
+	}
+	err := sender.SetState(context, playModeState(a.action, state.playInfo))
+	if err != nil {
+		return err
+	}
+	return a.show(sender, context, state.playInfo)
+}
+
+// keyDown cycles the shuffle or repeat mode of net/usb
+func (a playModeKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+
+	var err error
+	if a.action == shuffleAction {
+		err = device.ToggleNetUSBShuffle(context.Background())
+	} else {
+		err = device.ToggleNetUSBRepeat(context.Background())
+	}
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	playInfo, err := device.GetNetUSBPlayInfo(context.Background())
+	if err != nil {
+		return err
+	}
+
+	setStateDelayed(sender, sdContext, playModeState(a.action, playInfo))
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{playInfo: playInfo}, sdContext)
+	return a.show(sender, sdContext, playInfo)
+}
+
+// show shows the current shuffle or repeat mode as title
+func (a playModeKey) show(sender sdplugin.Sender, sdContext string, playInfo *musiccast.PlayInfo) error {
+	mode := playInfo.Repeat
+	if a.action == shuffleAction {
+		mode = playInfo.Shuffle
+	}
+	return sender.SetTitle(sdContext, displayName(mode), sdplugin.TargetBoth)
+}
+
+// playModeState returns the streamdeck state for the shuffle or repeat mode
+func playModeState(action string, playInfo *musiccast.PlayInfo) int {
+	if action == shuffleAction {
+		if playInfo.Shuffle == musiccast.ShuffleOff || playInfo.Shuffle == "" {
+			return 0 // off
+		}
+		return 1 // on, songs or albums
+	}
+
+	switch playInfo.Repeat {
+	case musiccast.RepeatOne:
+		return 1
+	case musiccast.RepeatAll:
+		return 2
+	}
+	return 0 // off
+}
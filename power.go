@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// powerKey toggles the power of a device
+type powerKey struct {
+	handler *musicCastHandler
+}
+
+func (a powerKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a powerKey) render(sender sdplugin.Sender, context string, _ actionContext, state deviceState) error {
+	targetState := powerState(state.status)
+	log.Printf("Settings state to %v\n", targetState)
+	return sender.SetState(context, targetState)
+}
+
+// keyDown toggles the power of the device
+func (a powerKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+	err := device.SetPower(context.Background(), musiccast.PowerToggle)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	log.Printf("Is MusicCast device on? %v", status.IsOn())
+
+	setStateDelayed(sender, sdContext, powerState(status))
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return nil
+}
+
+// setStateDelayed sets the state after a keyDown of an action with multiple states.
+// setState is delayed for one second to avoid wrong values, because streamdeck switches button by iteself shortly after press
+func setStateDelayed(sender sdplugin.Sender, sdContext string, targetState int) {
+	go func() {
+		time.Sleep(time.Second)
+		err := sender.SetState(sdContext, targetState)
+		if err != nil {
+			return
+		}
+		log.Printf("State set to: %v", targetState)
+	}()
+}
+
+// powerState returns the streamdeck state for the power status of the device
+func powerState(status *musiccast.Status) int {
+	if status.IsOn() {
+		return 0 // on
+	}
+	return 1 // off
+}
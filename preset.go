@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// presetKey plays a net/usb preset
+type presetKey struct {
+	handler *musicCastHandler
+}
+
+func (a presetKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a presetKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	return a.show(sender, context, actionContext.settings)
+}
+
+// keyDown plays the configured net/usb preset in the configured zone
+func (a presetKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	if settings.Preset == 0 {
+		sender.ShowAlert(sdContext)
+		return errors.New("No preset configured")
+	}
+
+	err := a.handler.device(settings).RecallNetUSBPreset(context.Background(), settings.Preset)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	sender.ShowOk(sdContext)
+	a.handler.refreshDevice(settings.deviceKey())
+	return nil
+}
+
+// show shows the name of the configured preset as title.
+// A title set by the user takes precedence.
+func (a presetKey) show(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	if settings.Preset == 0 {
+		return nil
+	}
+
+	presetInfo, err := a.handler.presetInfo(settings.deviceKey().ip)
+	if err != nil {
+		log.Printf("Could not fetch presets: %v\n", err)
+		return nil
+	}
+
+	title := ""
+	if settings.Preset <= len(presetInfo.PresetInfo) {
+		title = presetInfo.PresetInfo[settings.Preset-1].Text
+	}
+	return sender.SetTitle(sdContext, title, sdplugin.TargetBoth)
+}
+
+// presetInfo returns the net/usb presets of the device at ip.
+// They are cached until the device reports a change.
+func (m *musicCastHandler) presetInfo(ip string) (*musiccast.PresetInfo, error) {
+	m.presetMapMutex.Lock()
+	presetInfo, ok := m.presetMap[ip]
+	m.presetMapMutex.Unlock()
+	if ok {
+		return presetInfo, nil
+	}
+
+	presetInfo, err := musiccast.NewClient(ip, m.httpClient).GetNetUSBPresetInfo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	m.presetMapMutex.Lock()
+	m.presetMap[ip] = presetInfo
+	m.presetMapMutex.Unlock()
+	return presetInfo, nil
+}
+
+// presetInfoChanged drops the cached presets of the device at ip
+func (m *musicCastHandler) presetInfoChanged(ip string) {
+	m.presetMapMutex.Lock()
+	delete(m.presetMap, ip)
+	m.presetMapMutex.Unlock()
+}
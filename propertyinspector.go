@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// propertyInspectorData is sent to the property inspector.
+// It contains the settings and the choices the device offers.
+type propertyInspectorData struct {
+	Settings Settings `json:"settings"`
+	// Devices found by discovery
+	Devices []musiccast.DiscoveredDevice `json:"devices"`
+	// Zones of the device, empty if the device is not reachable
+	Zones []string `json:"zones"`
+	// Inputs of the configured zone, empty if the device is not reachable
+	Inputs []string `json:"inputs"`
+	// SceneNum is the number of scenes of the configured zone, 0 if the device is not reachable
+	SceneNum int `json:"sceneNum"`
+	// SoundPrograms of the configured zone, empty if the device is not reachable
+	SoundPrograms []string `json:"soundPrograms"`
+	// SurroundDecoders of the configured zone, empty if it has none or the device is not reachable
+	SurroundDecoders []string `json:"surroundDecoders"`
+	// Functions the zone can switch on and off, empty if the device is not reachable
+	Functions []string `json:"functions"`
+	// Tones of the zone, empty if it has no tone control or the device is not reachable
+	Tones []string `json:"tones"`
+	// Bands of the tuner, empty if the device has no tuner or is not reachable
+	Bands []string `json:"bands"`
+	// TunerPresetNum is the number of tuner presets, 0 if the device is not reachable
+	TunerPresetNum int `json:"tunerPresetNum"`
+	// Presets of net/usb, only sent to the preset action
+	Presets []propertyInspectorPreset `json:"presets"`
+}
+
+// propertyInspectorPreset is a stored net/usb preset the user can pick
+type propertyInspectorPreset struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+}
+
+// sendPropertyInspectorData sends settings and device features to the property inspector
+func (m *musicCastHandler) sendPropertyInspectorData(sender sdplugin.Sender, sdContext string, action string, settings Settings) error {
+	data := propertyInspectorData{
+		Settings:         settings,
+		Devices:          m.knownDevices(),
+		Zones:            []string{},
+		Inputs:           []string{},
+		SoundPrograms:    []string{},
+		SurroundDecoders: []string{},
+		Functions:        []string{},
+		Tones:            []string{},
+		Bands:            []string{},
+		Presets:          []propertyInspectorPreset{},
+	}
+
+	if settings.deviceKey().ip != "" {
+		features, err := m.features(settings.deviceKey().ip)
+		if err != nil {
+			log.Printf("Could not fetch device features: %v\n", err)
+		} else {
+			for _, zone := range features.Zone {
+				data.Zones = append(data.Zones, zone.ID)
+			}
+
+			data.Inputs = features.System.InputIDs()
+			if zone := features.ZoneFeatures(settings.deviceKey().zone); zone != nil {
+				data.Inputs = zone.InputList
+				data.SceneNum = zone.SceneNum
+				data.SoundPrograms = zone.SoundProgramList
+				data.SurroundDecoders = zone.SurrDecoderTypeList
+				for _, function := range soundFunctionIDs {
+					if zone.HasFunc(function) {
+						data.Functions = append(data.Functions, function)
+					}
+				}
+				if zone.HasFunc(toneControlFunc) {
+					data.Tones = toneControls
+				}
+			}
+
+			for _, band := range tunerBands {
+				if features.Tuner.HasFunc(band) {
+					data.Bands = append(data.Bands, band)
+				}
+			}
+			data.TunerPresetNum = features.Tuner.Preset.Num
+		}
+	}
+
+	if settings.deviceKey().ip != "" && action == presetAction {
+		presetInfo, err := m.presetInfo(settings.deviceKey().ip)
+		if err != nil {
+			log.Printf("Could not fetch presets: %v\n", err)
+		} else {
+			for i, preset := range presetInfo.PresetInfo {
+				if preset.Input == musiccast.PresetInputUnknown {
+					continue
+				}
+				data.Presets = append(data.Presets, propertyInspectorPreset{
+					Number: i + 1,
+					Name:   fmt.Sprintf("%v (%v)", preset.Text, preset.Input),
+				})
+			}
+		}
+	}
+
+	return sender.SendToPropertyInspector(sdContext, action, &data)
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// pollInterval of devices not sending events
+const pollInterval = 10 * time.Second
+
+// verifyInterval in which a poller checks the identity of a device that can not be verified
+const verifyInterval = time.Minute
+
+// deviceState is the cached state of a device zone.
+// Parts which were not fetched yet are nil.
+type deviceState struct {
+	status *musiccast.Status
+	// playInfo of net/usb, only fetched if a subscribed action needs it
+	playInfo *musiccast.PlayInfo
+	// cdPlayInfo, only fetched if a subscribed action needs play info and cd is the current input
+	cdPlayInfo *musiccast.CDPlayInfo
+	// tunerPlayInfo, only fetched if a subscribed action needs it
+	tunerPlayInfo *musiccast.TunerPlayInfo
+	// distInfo of MusicCast Link, only fetched if a subscribed action needs it
+	distInfo *musiccast.DistributionInfo
+	// funcStatus of the system, only fetched if a subscribed action needs it
+	funcStatus *musiccast.FuncStatus
+}
+
+// merge returns the state updated with all parts set in update
+func (s deviceState) merge(update deviceState) deviceState {
+	if update.status != nil {
+		s.status = update.status
+	}
+	if update.playInfo != nil {
+		s.playInfo = update.playInfo
+	}
+	if update.cdPlayInfo != nil {
+		s.cdPlayInfo = update.cdPlayInfo
+	}
+	if update.tunerPlayInfo != nil {
+		s.tunerPlayInfo = update.tunerPlayInfo
+	}
+	if update.distInfo != nil {
+		s.distInfo = update.distInfo
+	}
+	if update.funcStatus != nil {
+		s.funcStatus = update.funcStatus
+	}
+	return s
+}
+
+// playback returns the playback of the current input if it is known
+func (s deviceState) playback() (string, bool) {
+	if s.status == nil {
+		return "", false
+	}
+	if s.status.Input == cdInput {
+		if s.cdPlayInfo == nil {
+			return "", false
+		}
+		return s.cdPlayInfo.Playback, true
+	}
+	if s.playInfo == nil {
+		return "", false
+	}
+	if s.playInfo.Input != s.status.Input {
+		// current input has no net/usb playback
+		return musiccast.PlaybackStop, true
+	}
+	return s.playInfo.Playback, true
+}
+
+// devicePoller keeps the state of one device zone up to date for all contexts showing it.
+// The poller stops when the last context unsubscribes.
+type devicePoller struct {
+	// contexts subscribed to the device zone
+	contexts map[string]bool
+	// latest state
+	state deviceState
+	// refresh triggers an immediate fetch
+	refresh chan struct{}
+	cancel  context.CancelFunc
+}
+
+// subscribe the context to the device zone and start a poller if it is the first one.
+// Returns the cached state of the device zone.
+func (m *musicCastHandler) subscribe(sender sdplugin.Sender, key deviceKey, sdContext string) deviceState {
+	m.pollerMapMutex.Lock()
+	defer m.pollerMapMutex.Unlock()
+
+	poller, ok := m.pollerMap[key]
+	if !ok {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		poller = &devicePoller{
+			contexts: make(map[string]bool),
+			refresh:  make(chan struct{}, 1),
+			cancel:   cancelFunc,
+		}
+		m.pollerMap[key] = poller
+		go m.pollWorker(ctx, sender, key, poller.refresh)
+	}
+	poller.contexts[sdContext] = true
+
+	return poller.state
+}
+
+// unsubscribe the context from the device zone and stop the poller if it was the last one
+func (m *musicCastHandler) unsubscribe(key deviceKey, sdContext string) {
+	m.pollerMapMutex.Lock()
+	defer m.pollerMapMutex.Unlock()
+
+	poller, ok := m.pollerMap[key]
+	if !ok {
+		return
+	}
+
+	delete(poller.contexts, sdContext)
+	if len(poller.contexts) == 0 {
+		poller.cancel()
+		delete(m.pollerMap, key)
+	}
+}
+
+// refreshDeviceZones lets the pollers of all zones of the device fetch the status immediately
+func (m *musicCastHandler) refreshDeviceZones(ip string) {
+	m.pollerMapMutex.Lock()
+	keys := []deviceKey{}
+	for key := range m.pollerMap {
+		if key.ip == ip {
+			keys = append(keys, key)
+		}
+	}
+	m.pollerMapMutex.Unlock()
+
+	for _, key := range keys {
+		m.refreshDevice(key)
+	}
+}
+
+// refreshAllDevices lets all pollers fetch the status immediately
+func (m *musicCastHandler) refreshAllDevices() {
+	m.pollerMapMutex.Lock()
+	keys := make([]deviceKey, 0, len(m.pollerMap))
+	for key := range m.pollerMap {
+		keys = append(keys, key)
+	}
+	m.pollerMapMutex.Unlock()
+
+	for _, key := range keys {
+		m.refreshDevice(key)
+	}
+}
+
+// refreshDevice lets the poller of the device zone fetch the status immediately
+func (m *musicCastHandler) refreshDevice(key deviceKey) {
+	m.pollerMapMutex.Lock()
+	defer m.pollerMapMutex.Unlock()
+
+	if poller, ok := m.pollerMap[key]; ok {
+		// a pending refresh is good enough
+		select {
+		case poller.refresh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// pollWorker fetches the status of the device zone and updates all subscribed contexts
+func (m *musicCastHandler) pollWorker(ctx context.Context, sender sdplugin.Sender, key deviceKey, refresh <-chan struct{}) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	// verified is true if the device answered with the expected device id
+	verified := false
+	var lastVerify time.Time
+	var lastUpdate time.Time
+	// renew is true if the poll only renews the subscription of a device sending events
+	update := func(renew bool) {
+		previous := m.cachedStatus(key)
+		err := m.poll(ctx, sender, key)
+		if renew {
+			m.checkSubscription(key.ip, previous, m.cachedStatus(key), lastUpdate, err)
+		}
+		if (err != nil || !verified) && time.Since(lastVerify) >= verifyInterval {
+			verified = m.verifyDevice(ctx, sender, key)
+			lastVerify = time.Now()
+		}
+		lastUpdate = time.Now()
+	}
+
+	log.Printf("Starting poller for %v %v\n", key.ip, key.zone)
+	update(false)
+	for true {
+		renew := false
+		select {
+		case <-ticker.C:
+			// devices sending events only need to be polled to renew the subscription
+			if m.receivesEvents(key.ip) {
+				if time.Since(lastUpdate) < eventRenewInterval {
+					continue
+				}
+				renew = true
+			}
+		case <-refresh:
+		case <-ctx.Done():
+			log.Printf("Stopping poller for %v %v\n", key.ip, key.zone)
+			return
+		}
+
+		update(renew)
+	}
+}
+
+// cachedStatus returns the cached status of the device zone, nil if it is unknown
+func (m *musicCastHandler) cachedStatus(key deviceKey) *musiccast.Status {
+	m.pollerMapMutex.Lock()
+	defer m.pollerMapMutex.Unlock()
+
+	if poller, ok := m.pollerMap[key]; ok {
+		return poller.state.status
+	}
+	return nil
+}
+
+// poll fetches the state of the device zone once.
+// Only parts needed by the subscribed actions are fetched.
+func (m *musicCastHandler) poll(ctx context.Context, sender sdplugin.Sender, key deviceKey) error {
+	device := m.deviceFor(key)
+	status, err := device.GetStatus(ctx)
+	if err != nil {
+		log.Printf("Could not fetch device status: %v\n", err)
+		return err
+	}
+	state := deviceState{status: status}
+
+	if m.subscribersNeed(key, needsPlayInfo) {
+		playInfo, err := device.GetNetUSBPlayInfo(ctx)
+		if err != nil {
+			log.Printf("Could not fetch play info: %v\n", err)
+		} else {
+			state.playInfo = playInfo
+		}
+
+		if status.Input == cdInput {
+			cdPlayInfo, err := device.GetCDPlayInfo(ctx)
+			if err != nil {
+				log.Printf("Could not fetch CD play info: %v\n", err)
+			} else {
+				state.cdPlayInfo = cdPlayInfo
+			}
+		}
+	}
+
+	if m.subscribersNeed(key, needsTunerPlayInfo) {
+		tunerPlayInfo, err := device.GetTunerPlayInfo(ctx)
+		if err != nil {
+			log.Printf("Could not fetch tuner play info: %v\n", err)
+		} else {
+			state.tunerPlayInfo = tunerPlayInfo
+		}
+	}
+
+	if m.subscribersNeed(key, needsDistributionInfo) {
+		distInfo, err := device.GetDistributionInfo(ctx)
+		if err != nil {
+			log.Printf("Could not fetch distribution info: %v\n", err)
+		} else {
+			state.distInfo = distInfo
+		}
+	}
+
+	if m.subscribersNeed(key, needsFuncStatus) {
+		funcStatus, err := device.GetFuncStatus(ctx)
+		if err != nil {
+			log.Printf("Could not fetch function status: %v\n", err)
+		} else {
+			state.funcStatus = funcStatus
+		}
+	}
+
+	m.deviceChanged(sender, key, state, "")
+	return nil
+}
+
+// subscribersNeed reports if any action subscribed to the device zone needs the data
+func (m *musicCastHandler) subscribersNeed(key deviceKey, needs func(action string) bool) bool {
+	for _, actionContext := range m.subscribedContexts(key) {
+		if needs(actionContext.action) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribedContexts returns the contexts subscribed to the device zone with their settings
+func (m *musicCastHandler) subscribedContexts(key deviceKey) map[string]actionContext {
+	m.pollerMapMutex.Lock()
+	contexts := []string{}
+	if poller, ok := m.pollerMap[key]; ok {
+		for context := range poller.contexts {
+			contexts = append(contexts, context)
+		}
+	}
+	m.pollerMapMutex.Unlock()
+
+	m.contextMapMutex.Lock()
+	defer m.contextMapMutex.Unlock()
+	actionContexts := make(map[string]actionContext)
+	for _, context := range contexts {
+		if actionContext, ok := m.contextMap[context]; ok {
+			actionContexts[context] = actionContext
+		}
+	}
+	return actionContexts
+}
+
+// deviceChanged caches the state and updates all contexts subscribed to the device zone
+// except the pressed one, which is updated by the keyDown handler itself
+func (m *musicCastHandler) deviceChanged(sender sdplugin.Sender, key deviceKey, update deviceState, pressedContext string) {
+	m.pollerMapMutex.Lock()
+	poller, ok := m.pollerMap[key]
+	if !ok {
+		m.pollerMapMutex.Unlock()
+		return
+	}
+	poller.state = poller.state.merge(update)
+	state := poller.state
+	contexts := make([]string, 0, len(poller.contexts))
+	for context := range poller.contexts {
+		if context != pressedContext {
+			contexts = append(contexts, context)
+		}
+	}
+	m.pollerMapMutex.Unlock()
+
+	for _, context := range contexts {
+		m.contextMapMutex.Lock()
+		actionContext, ok := m.contextMap[context]
+		m.contextMapMutex.Unlock()
+		if !ok {
+			continue
+		}
+
+		err := m.renderContext(sender, context, actionContext, state)
+		if err != nil {
+			log.Printf("Failed to update key: %v\n", err)
+		}
+	}
+}
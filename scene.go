@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// sceneKey recalls a scene
+type sceneKey struct {
+	handler *musicCastHandler
+}
+
+func (a sceneKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+// keyDown recalls the configured scene of the zone
+func (a sceneKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	if settings.Scene == 0 {
+		sender.ShowAlert(sdContext)
+		return errors.New("No scene configured")
+	}
+
+	err := a.handler.device(settings).RecallScene(context.Background(), settings.Scene)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	sender.ShowOk(sdContext)
+	a.handler.refreshDevice(settings.deviceKey())
+	return nil
+}
@@ -0,0 +1,103 @@
+package sdplugin
+
+// Handler receives events from the streamdeck app. It can be any value implementing
+// one or more of the event handler interfaces below. Events without a matching
+// interface are ignored. Each event gets called on its own goroutine.
+// The Sender can be used to send data back to the streamdeck app.
+// Sender is implemented by Plugin itself.
+type Handler interface{}
+
+// KeyDownHandler handles keyDown events
+type KeyDownHandler interface {
+	HandleKeyDownEvent(sender Sender, event KeyEventMessage) error
+}
+
+// KeyUpHandler handles keyUp events
+type KeyUpHandler interface {
+	HandleKeyUpEvent(sender Sender, event KeyEventMessage) error
+}
+
+// DialDownHandler handles dialDown events of Stream Deck+ encoders
+type DialDownHandler interface {
+	HandleDialDownEvent(sender Sender, event DialEventMessage) error
+}
+
+// DialUpHandler handles dialUp events of Stream Deck+ encoders
+type DialUpHandler interface {
+	HandleDialUpEvent(sender Sender, event DialEventMessage) error
+}
+
+// DialRotateHandler handles dialRotate events of Stream Deck+ encoders
+type DialRotateHandler interface {
+	HandleDialRotateEvent(sender Sender, event DialRotateEventMessage) error
+}
+
+// TouchTapHandler handles touchTap events of the Stream Deck+ touch strip
+type TouchTapHandler interface {
+	HandleTouchTapEvent(sender Sender, event TouchTapEventMessage) error
+}
+
+// WillAppearHandler handles willAppear events
+type WillAppearHandler interface {
+	HandleWillAppearEvent(sender Sender, event AppearanceEventMessage) error
+}
+
+// WillDisappearHandler handles willDisappear events
+type WillDisappearHandler interface {
+	HandleWillDisappearEvent(sender Sender, event AppearanceEventMessage) error
+}
+
+// SendToPluginHandler handles sendToPlugin events
+type SendToPluginHandler interface {
+	HandleSendToPluginEvent(sender Sender, event SendToPluginEventMessage) error
+}
+
+// TitleParametersDidChangeHandler handles titleParametersDidChange events
+type TitleParametersDidChangeHandler interface {
+	HandleTitleParametersDidChangeEvent(sender Sender, event TitleParametersDidChangeEventMessage) error
+}
+
+// DidReceiveSettingsHandler handles didReceiveSettings events
+type DidReceiveSettingsHandler interface {
+	HandleDidReceiveSettingsEvent(sender Sender, event DidReceiveSettingsEventMessage) error
+}
+
+// PropertyInspectorDidAppearHandler handles propertyInspectorDidAppear events
+type PropertyInspectorDidAppearHandler interface {
+	HandlePropertyInspectorDidAppearEvent(sender Sender, event PropertyInspectorEventMessage) error
+}
+
+// PropertyInspectorDidDisappearHandler handles propertyInspectorDidDisappear events
+type PropertyInspectorDidDisappearHandler interface {
+	HandlePropertyInspectorDidDisappearEvent(sender Sender, event PropertyInspectorEventMessage) error
+}
+
+// DeviceDidConnectHandler handles deviceDidConnect events
+type DeviceDidConnectHandler interface {
+	HandleDeviceDidConnectEvent(sender Sender, event DeviceDidConnectEventMessage) error
+}
+
+// DeviceDidDisconnectHandler handles deviceDidDisconnect events
+type DeviceDidDisconnectHandler interface {
+	HandleDeviceDidDisconnectEvent(sender Sender, event DeviceDidDisconnectEventMessage) error
+}
+
+// ApplicationDidLaunchHandler handles applicationDidLaunch events
+type ApplicationDidLaunchHandler interface {
+	HandleApplicationDidLaunchEvent(sender Sender, event ApplicationEventMessage) error
+}
+
+// ApplicationDidTerminateHandler handles applicationDidTerminate events
+type ApplicationDidTerminateHandler interface {
+	HandleApplicationDidTerminateEvent(sender Sender, event ApplicationEventMessage) error
+}
+
+// DidReceiveGlobalSettingsHandler handles didReceiveGlobalSettings events
+type DidReceiveGlobalSettingsHandler interface {
+	HandleDidReceiveGlobalSettingsEvent(sender Sender, event DidReceiveGlobalSettingsEventMessage) error
+}
+
+// SystemDidWakeUpHandler handles systemDidWakeUp events
+type SystemDidWakeUpHandler interface {
+	HandleSystemDidWakeUpEvent(sender Sender, event SystemDidWakeUpEventMessage) error
+}
@@ -32,6 +32,60 @@ type KeyPayload struct {
 	IsInMultiAction  bool            `json:"isInMultiAction"`
 }
 
+// DialEventMessage contains all data from dialDown and dialUp events of Stream Deck+ encoders
+type DialEventMessage struct {
+	Event   string      `json:"event"`
+	Action  string      `json:"action"`
+	Context string      `json:"context"`
+	Device  string      `json:"device"`
+	Payload DialPayload `json:"payload"`
+}
+
+// DialPayload data in DialEventMessage
+type DialPayload struct {
+	Settings    json.RawMessage `json:"settings"`
+	Coordinates Coordinates     `json:"coordinates"`
+	Controller  string          `json:"controller"`
+}
+
+// DialRotateEventMessage contains all data from the dialRotate event
+type DialRotateEventMessage struct {
+	Event   string            `json:"event"`
+	Action  string            `json:"action"`
+	Context string            `json:"context"`
+	Device  string            `json:"device"`
+	Payload DialRotatePayload `json:"payload"`
+}
+
+// DialRotatePayload data in DialRotateEventMessage
+type DialRotatePayload struct {
+	Settings    json.RawMessage `json:"settings"`
+	Coordinates Coordinates     `json:"coordinates"`
+	// Ticks the dial was rotated, negative counterclockwise
+	Ticks int `json:"ticks"`
+	// Pressed is true if the dial was pressed while rotating
+	Pressed bool `json:"pressed"`
+}
+
+// TouchTapEventMessage contains all data from the touchTap event of the Stream Deck+ touch strip
+type TouchTapEventMessage struct {
+	Event   string          `json:"event"`
+	Action  string          `json:"action"`
+	Context string          `json:"context"`
+	Device  string          `json:"device"`
+	Payload TouchTapPayload `json:"payload"`
+}
+
+// TouchTapPayload data in TouchTapEventMessage
+type TouchTapPayload struct {
+	Settings    json.RawMessage `json:"settings"`
+	Coordinates Coordinates     `json:"coordinates"`
+	// TapPos is the x and y position of the tap relative to the area of the action
+	TapPos [2]int `json:"tapPos"`
+	// Hold is true for a long touch
+	Hold bool `json:"hold"`
+}
+
 // AppearanceEventMessage contains all data from willAppear and willDisappear events
 type AppearanceEventMessage struct {
 	Event   string            `json:"event"`
@@ -47,6 +101,8 @@ type AppearancePayload struct {
 	Coordinates     Coordinates     `json:"coordinates"`
 	State           int             `json:"state"`
 	IsInMultiAction bool            `json:"isInMultiAction"`
+	// Controller is "Keypad" or "Encoder"
+	Controller string `json:"controller"`
 }
 
 // SendToPluginEventMessage contains data send from PropertyInspector
@@ -121,3 +177,44 @@ type ApplicationEventMessage struct {
 type ApplicationPayload struct {
 	Application string `json:"application"`
 }
+
+// DidReceiveSettingsEventMessage contains all data from the didReceiveSettings event
+type DidReceiveSettingsEventMessage struct {
+	Event   string                    `json:"event"`
+	Action  string                    `json:"action"`
+	Context string                    `json:"context"`
+	Device  string                    `json:"device"`
+	Payload DidReceiveSettingsPayload `json:"payload"`
+}
+
+// DidReceiveSettingsPayload data in DidReceiveSettingsEventMessage
+type DidReceiveSettingsPayload struct {
+	Settings        json.RawMessage `json:"settings"`
+	Coordinates     Coordinates     `json:"coordinates"`
+	IsInMultiAction bool            `json:"isInMultiAction"`
+}
+
+// PropertyInspectorEventMessage contains all data from the propertyInspectorDidAppear
+// and propertyInspectorDidDisappear events
+type PropertyInspectorEventMessage struct {
+	Event   string `json:"event"`
+	Action  string `json:"action"`
+	Context string `json:"context"`
+	Device  string `json:"device"`
+}
+
+// SystemDidWakeUpEventMessage contains all data from the systemDidWakeUp event
+type SystemDidWakeUpEventMessage struct {
+	Event string `json:"event"`
+}
+
+// DidReceiveGlobalSettingsEventMessage contains all data from the didReceiveGlobalSettings event
+type DidReceiveGlobalSettingsEventMessage struct {
+	Event   string                `json:"event"`
+	Payload GlobalSettingsPayload `json:"payload"`
+}
+
+// GlobalSettingsPayload data in DidReceiveGlobalSettingsEventMessage
+type GlobalSettingsPayload struct {
+	Settings json.RawMessage `json:"settings"`
+}
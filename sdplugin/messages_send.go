@@ -10,6 +10,16 @@ const (
 	TargetBoth     = "both"     // TargetBoth will show in software and on streamdeck
 )
 
+// Built-in layouts of the Stream Deck+ touch strip for SetFeedbackLayout
+const (
+	LayoutIcon      = "$X1" // LayoutIcon shows title and a big icon
+	LayoutCanvas    = "$A0" // LayoutCanvas shows title and a full width image
+	LayoutValue     = "$A1" // LayoutValue shows title, icon and value
+	LayoutBar       = "$B1" // LayoutBar shows title, icon, value and a bar
+	LayoutDoubleBar = "$B2" // LayoutDoubleBar shows title, icon, value and a bar with an indicator
+	LayoutDualBar   = "$C1" // LayoutDualBar shows title and two bars with icons
+)
+
 // RegisterEventMessage must be sent as first message. This happens automaticaly in the New(...) function.
 type RegisterEventMessage struct {
 	Event string `json:"event"`
@@ -36,13 +46,19 @@ type SendToPropertyInspectorEventMessage struct {
 	Payload json.RawMessage `json:"payload"`
 }
 
-// SetSettingsEventMessage contains all data needed for SetSettingsEvent
+// SetSettingsEventMessage contains all data needed for setSettings and setGlobalSettings events
 type SetSettingsEventMessage struct {
 	Event   string          `json:"event"`
 	Context string          `json:"context"`
 	Payload json.RawMessage `json:"payload"`
 }
 
+// GetSettingsEventMessage contains all data needed for getSettings and getGlobalSettings events
+type GetSettingsEventMessage struct {
+	Event   string `json:"event"`
+	Context string `json:"context"`
+}
+
 // ShowNotifyEventMessage contains all data needed for showAlert and showOk events
 type ShowNotifyEventMessage struct {
 	Event   string `json:"event"`
@@ -102,3 +118,54 @@ type OpenURLEventMessage struct {
 type OpenURLPayload struct {
 	URL string `json:"url"`
 }
+
+// SetFeedbackEventMessage contains all data needed for setFeedback event
+type SetFeedbackEventMessage struct {
+	Event   string          `json:"event"`
+	Context string          `json:"context"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// BarFeedback is the payload of SetFeedback for LayoutBar and LayoutDoubleBar.
+// Only set fields are changed.
+type BarFeedback struct {
+	Title     string `json:"title,omitempty"`
+	Icon      string `json:"icon,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Indicator *Bar   `json:"indicator,omitempty"`
+}
+
+// Bar of the touch strip layouts
+type Bar struct {
+	// Value from 0 to 100
+	Value int `json:"value"`
+}
+
+// SetFeedbackLayoutEventMessage contains all data needed for setFeedbackLayout event
+type SetFeedbackLayoutEventMessage struct {
+	Event   string                   `json:"event"`
+	Context string                   `json:"context"`
+	Payload SetFeedbackLayoutPayload `json:"payload"`
+}
+
+// SetFeedbackLayoutPayload data for SetFeedbackLayoutEventMessage
+type SetFeedbackLayoutPayload struct {
+	// One of the Layout constants or the path of a custom layout file
+	Layout string `json:"layout"`
+}
+
+// SetTriggerDescriptionEventMessage contains all data needed for setTriggerDescription event
+type SetTriggerDescriptionEventMessage struct {
+	Event   string             `json:"event"`
+	Context string             `json:"context"`
+	Payload TriggerDescription `json:"payload"`
+}
+
+// TriggerDescription describes what the interactions with an encoder do.
+// Empty descriptions are reset to the ones of the manifest.
+type TriggerDescription struct {
+	Rotate    string `json:"rotate,omitempty"`
+	Push      string `json:"push,omitempty"`
+	Touch     string `json:"touch,omitempty"`
+	LongTouch string `json:"longTouch,omitempty"`
+}
@@ -1,7 +1,10 @@
 // Package sdplugin implements a simple wrapper around the streamdeck API.
 //
 // Create an instance of Plugin with New(handler Handler) and provide your own
-// Handler implementation.
+// Handler implementation. A Handler implements only the event handler interfaces,
+// e.g. KeyDownHandler, for the events it is interested in. Events of an action can be
+// routed to a separate Handler with Register(action, handler), events it does not
+// implement still reach the default Handler.
 package sdplugin
 
 import (
@@ -27,23 +30,6 @@ func init() {
 	flag.StringVar(&pluginUUID, "pluginUUID", "", "UUID of plugin")
 	flag.StringVar(&registerEvent, "registerEvent", "", "Name of register event")
 	flag.StringVar(&info, "info", "", "JSON info object from StreamDeck app")
-	flag.Parse()
-}
-
-// Handler must implement all possible events. Each event gets called on its own goroutine.
-// The Sender can bee used to send data back to the streamdeck app.
-// Sender is implemented by Plugin itself.
-type Handler interface {
-	HandleKeyDownEvent(sender Sender, event KeyEventMessage) error
-	HandleKeyUpEvent(sender Sender, event KeyEventMessage) error
-	HandleWillAppearEvent(sender Sender, event AppearanceEventMessage) error
-	HandleWillDisappearEvent(sender Sender, event AppearanceEventMessage) error
-	HandleSendToPluginEvent(sender Sender, event SendToPluginEventMessage) error
-	HandleTitleParametersDidChangeEvent(sender Sender, event TitleParametersDidChangeEventMessage) error
-	HandleDeviceDidConnectEvent(sender Sender, event DeviceDidConnectEventMessage) error
-	HandleDeviceDidDisconnectEvent(sender Sender, event DeviceDidDisconnectEventMessage) error
-	HandleApplicationDidLaunchEvent(sender Sender, event ApplicationEventMessage) error
-	HandleApplicationDidTerminateEvent(sender Sender, event ApplicationEventMessage) error
 }
 
 // Plugin communicates with StreamDeck websocket.
@@ -54,10 +40,18 @@ type Plugin struct {
 	connSendMutex *sync.Mutex
 	conn          *websocket.Conn
 	handler       Handler
+	// actionHandlers by action UUID, see Register
+	actionHandlers map[string]Handler
 }
 
 // New plugin instance. The instance is already registered with the streamdeck app.
+// handler receives all events which are not routed to an action handler.
 func New(handler Handler) (*Plugin, error) {
+	// parsed here instead of init, so the flags of test binaries do not fail the parsing
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
 	// connect to streamdeck app
 	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://localhost:%v", port), nil)
 	if err != nil {
@@ -72,15 +66,41 @@ func New(handler Handler) (*Plugin, error) {
 	conn.WriteJSON(&registerEventMessage)
 
 	return &Plugin{
-		connSendMutex: &sync.Mutex{},
-		conn:          conn,
-		handler:       handler,
+		connSendMutex:  &sync.Mutex{},
+		conn:           conn,
+		handler:        handler,
+		actionHandlers: make(map[string]Handler),
 	}, nil
 }
 
+// Register a handler for all events of the action with the given UUID.
+// Events the handler does not implement are passed to the default handler.
+// Must be called before Run.
+func (p *Plugin) Register(action string, handler Handler) {
+	p.actionHandlers[action] = handler
+}
+
+// handlersFor returns the handler registered for the action followed by the default handler.
+// An event is handled by the first of them implementing its handler interface.
+func (p *Plugin) handlersFor(action string) []Handler {
+	if handler, ok := p.actionHandlers[action]; ok {
+		return []Handler{handler, p.handler}
+	}
+	return []Handler{p.handler}
+}
+
+// handle calls the handler function on its own goroutine and logs errors
+func (p *Plugin) handle(handlerFunc func() error) {
+	go func() {
+		err := handlerFunc()
+		if err != nil {
+			log.Println(err)
+		}
+	}()
+}
+
 // Run plugin and receive messages in a loop.
 func (p *Plugin) Run() error {
-	baseEventMessage := BaseEventMessage{}
 	for true {
 		// raw message
 		messageType, data, err := p.conn.ReadMessage()
@@ -93,135 +113,227 @@ func (p *Plugin) Run() error {
 			continue
 		}
 
-		// parsed to get event name
-		err = json.Unmarshal(data, &baseEventMessage)
+		err = p.dispatch(data)
 		if err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		// parse and handle different event types
-		if baseEventMessage.Event == "keyDown" || baseEventMessage.Event == "keyUp" {
-			var keyEventMessage KeyEventMessage
-			err = json.Unmarshal(data, &keyEventMessage)
-			if err != nil {
-				return err
-			}
+// dispatch parses a raw message and passes it to the handler of its event
+func (p *Plugin) dispatch(data []byte) error {
+	// parsed to get event name
+	baseEventMessage := BaseEventMessage{}
+	err := json.Unmarshal(data, &baseEventMessage)
+	if err != nil {
+		return err
+	}
+
+	// parse and handle different event types
+	if baseEventMessage.Event == "keyDown" || baseEventMessage.Event == "keyUp" {
+		var keyEventMessage KeyEventMessage
+		err = json.Unmarshal(data, &keyEventMessage)
+		if err != nil {
+			return err
+		}
 
+		for _, handler := range p.handlersFor(keyEventMessage.Action) {
 			if baseEventMessage.Event == "keyDown" {
-				go func() {
-					err = p.handler.HandleKeyDownEvent(p, keyEventMessage)
-					if err != nil {
-						log.Println(err)
-					}
-				}()
+				if h, ok := handler.(KeyDownHandler); ok {
+					p.handle(func() error { return h.HandleKeyDownEvent(p, keyEventMessage) })
+					break
+				}
 			} else {
-				go func() {
-					err = p.handler.HandleKeyUpEvent(p, keyEventMessage)
-					if err != nil {
-						log.Println(err)
-					}
-				}()
-			}
-		} else if baseEventMessage.Event == "willAppear" || baseEventMessage.Event == "willDisappear" {
-			var appearanceEventMessage AppearanceEventMessage
-			err = json.Unmarshal(data, &appearanceEventMessage)
-			if err != nil {
-				return err
+				if h, ok := handler.(KeyUpHandler); ok {
+					p.handle(func() error { return h.HandleKeyUpEvent(p, keyEventMessage) })
+					break
+				}
 			}
+		}
+	} else if baseEventMessage.Event == "dialDown" || baseEventMessage.Event == "dialUp" {
+		var dialEventMessage DialEventMessage
+		err = json.Unmarshal(data, &dialEventMessage)
+		if err != nil {
+			return err
+		}
 
-			if baseEventMessage.Event == "willAppear" {
-				go func() {
-					err = p.handler.HandleWillAppearEvent(p, appearanceEventMessage)
-					if err != nil {
-						log.Println(err)
-					}
-				}()
+		for _, handler := range p.handlersFor(dialEventMessage.Action) {
+			if baseEventMessage.Event == "dialDown" {
+				if h, ok := handler.(DialDownHandler); ok {
+					p.handle(func() error { return h.HandleDialDownEvent(p, dialEventMessage) })
+					break
+				}
 			} else {
-				go func() {
-					err = p.handler.HandleWillDisappearEvent(p, appearanceEventMessage)
-					if err != nil {
-						log.Println(err)
-					}
-				}()
+				if h, ok := handler.(DialUpHandler); ok {
+					p.handle(func() error { return h.HandleDialUpEvent(p, dialEventMessage) })
+					break
+				}
 			}
-		} else if baseEventMessage.Event == "sendToPlugin" {
-			var sendToPluginEventMessage SendToPluginEventMessage
-			err = json.Unmarshal(data, &sendToPluginEventMessage)
-			if err != nil {
-				return err
+		}
+	} else if baseEventMessage.Event == "dialRotate" {
+		var dialRotateEventMessage DialRotateEventMessage
+		err = json.Unmarshal(data, &dialRotateEventMessage)
+		if err != nil {
+			return err
+		}
+
+		for _, handler := range p.handlersFor(dialRotateEventMessage.Action) {
+			if h, ok := handler.(DialRotateHandler); ok {
+				p.handle(func() error { return h.HandleDialRotateEvent(p, dialRotateEventMessage) })
+				break
 			}
+		}
+	} else if baseEventMessage.Event == "touchTap" {
+		var touchTapEventMessage TouchTapEventMessage
+		err = json.Unmarshal(data, &touchTapEventMessage)
+		if err != nil {
+			return err
+		}
 
-			go func() {
-				err = p.handler.HandleSendToPluginEvent(p, sendToPluginEventMessage)
-				if err != nil {
-					log.Println(err)
-				}
-			}()
-		} else if baseEventMessage.Event == "titleParametersDidChange" {
-			var titleParametersDidChangeEventMessage TitleParametersDidChangeEventMessage
-			err = json.Unmarshal(data, &titleParametersDidChangeEventMessage)
-			if err != nil {
-				return err
+		for _, handler := range p.handlersFor(touchTapEventMessage.Action) {
+			if h, ok := handler.(TouchTapHandler); ok {
+				p.handle(func() error { return h.HandleTouchTapEvent(p, touchTapEventMessage) })
+				break
 			}
+		}
+	} else if baseEventMessage.Event == "willAppear" || baseEventMessage.Event == "willDisappear" {
+		var appearanceEventMessage AppearanceEventMessage
+		err = json.Unmarshal(data, &appearanceEventMessage)
+		if err != nil {
+			return err
+		}
 
-			go func() {
-				err = p.handler.HandleTitleParametersDidChangeEvent(p, titleParametersDidChangeEventMessage)
-				if err != nil {
-					log.Println(err)
+		for _, handler := range p.handlersFor(appearanceEventMessage.Action) {
+			if baseEventMessage.Event == "willAppear" {
+				if h, ok := handler.(WillAppearHandler); ok {
+					p.handle(func() error { return h.HandleWillAppearEvent(p, appearanceEventMessage) })
+					break
+				}
+			} else {
+				if h, ok := handler.(WillDisappearHandler); ok {
+					p.handle(func() error { return h.HandleWillDisappearEvent(p, appearanceEventMessage) })
+					break
 				}
-			}()
-		} else if baseEventMessage.Event == "deviceDidConnect" {
-			var deviceDidConnectEventMessage DeviceDidConnectEventMessage
-			err = json.Unmarshal(data, &deviceDidConnectEventMessage)
-			if err != nil {
-				return err
 			}
+		}
+	} else if baseEventMessage.Event == "sendToPlugin" {
+		var sendToPluginEventMessage SendToPluginEventMessage
+		err = json.Unmarshal(data, &sendToPluginEventMessage)
+		if err != nil {
+			return err
+		}
 
-			go func() {
-				err = p.handler.HandleDeviceDidConnectEvent(p, deviceDidConnectEventMessage)
-				if err != nil {
-					log.Println(err)
-				}
-			}()
-		} else if baseEventMessage.Event == "deviceDidDisconnect" {
-			var deviceDidDisconnectEventMessage DeviceDidDisconnectEventMessage
-			err = json.Unmarshal(data, &deviceDidDisconnectEventMessage)
-			if err != nil {
-				return err
+		for _, handler := range p.handlersFor(sendToPluginEventMessage.Action) {
+			if h, ok := handler.(SendToPluginHandler); ok {
+				p.handle(func() error { return h.HandleSendToPluginEvent(p, sendToPluginEventMessage) })
+				break
 			}
+		}
+	} else if baseEventMessage.Event == "titleParametersDidChange" {
+		var titleParametersDidChangeEventMessage TitleParametersDidChangeEventMessage
+		err = json.Unmarshal(data, &titleParametersDidChangeEventMessage)
+		if err != nil {
+			return err
+		}
 
-			go func() {
-				err = p.handler.HandleDeviceDidDisconnectEvent(p, deviceDidDisconnectEventMessage)
-				if err != nil {
-					log.Println(err)
-				}
-			}()
-		} else if baseEventMessage.Event == "applicationDidLaunch" || baseEventMessage.Event == "applicationDidTerminate" {
-			var applicationEventMessage ApplicationEventMessage
-			err = json.Unmarshal(data, &applicationEventMessage)
-			if err != nil {
-				return err
+		for _, handler := range p.handlersFor(titleParametersDidChangeEventMessage.Action) {
+			if h, ok := handler.(TitleParametersDidChangeHandler); ok {
+				p.handle(func() error { return h.HandleTitleParametersDidChangeEvent(p, titleParametersDidChangeEventMessage) })
+				break
 			}
+		}
+	} else if baseEventMessage.Event == "didReceiveSettings" {
+		var didReceiveSettingsEventMessage DidReceiveSettingsEventMessage
+		err = json.Unmarshal(data, &didReceiveSettingsEventMessage)
+		if err != nil {
+			return err
+		}
+
+		for _, handler := range p.handlersFor(didReceiveSettingsEventMessage.Action) {
+			if h, ok := handler.(DidReceiveSettingsHandler); ok {
+				p.handle(func() error { return h.HandleDidReceiveSettingsEvent(p, didReceiveSettingsEventMessage) })
+				break
+			}
+		}
+	} else if baseEventMessage.Event == "propertyInspectorDidAppear" || baseEventMessage.Event == "propertyInspectorDidDisappear" {
+		var propertyInspectorEventMessage PropertyInspectorEventMessage
+		err = json.Unmarshal(data, &propertyInspectorEventMessage)
+		if err != nil {
+			return err
+		}
 
-			if baseEventMessage.Event == "applicationDidLaunch" {
-				go func() {
-					err = p.handler.HandleApplicationDidLaunchEvent(p, applicationEventMessage)
-					if err != nil {
-						log.Println(err)
-					}
-				}()
+		for _, handler := range p.handlersFor(propertyInspectorEventMessage.Action) {
+			if baseEventMessage.Event == "propertyInspectorDidAppear" {
+				if h, ok := handler.(PropertyInspectorDidAppearHandler); ok {
+					p.handle(func() error { return h.HandlePropertyInspectorDidAppearEvent(p, propertyInspectorEventMessage) })
+					break
+				}
 			} else {
+				if h, ok := handler.(PropertyInspectorDidDisappearHandler); ok {
+					p.handle(func() error { return h.HandlePropertyInspectorDidDisappearEvent(p, propertyInspectorEventMessage) })
+					break
+				}
+			}
+		}
+	} else if baseEventMessage.Event == "deviceDidConnect" {
+		var deviceDidConnectEventMessage DeviceDidConnectEventMessage
+		err = json.Unmarshal(data, &deviceDidConnectEventMessage)
+		if err != nil {
+			return err
+		}
 
-				go func() {
-					err = p.handler.HandleApplicationDidTerminateEvent(p, applicationEventMessage)
-					if err != nil {
-						log.Println(err)
-					}
-				}()
+		if h, ok := p.handler.(DeviceDidConnectHandler); ok {
+			p.handle(func() error { return h.HandleDeviceDidConnectEvent(p, deviceDidConnectEventMessage) })
+		}
+	} else if baseEventMessage.Event == "deviceDidDisconnect" {
+		var deviceDidDisconnectEventMessage DeviceDidDisconnectEventMessage
+		err = json.Unmarshal(data, &deviceDidDisconnectEventMessage)
+		if err != nil {
+			return err
+		}
+
+		if h, ok := p.handler.(DeviceDidDisconnectHandler); ok {
+			p.handle(func() error { return h.HandleDeviceDidDisconnectEvent(p, deviceDidDisconnectEventMessage) })
+		}
+	} else if baseEventMessage.Event == "applicationDidLaunch" || baseEventMessage.Event == "applicationDidTerminate" {
+		var applicationEventMessage ApplicationEventMessage
+		err = json.Unmarshal(data, &applicationEventMessage)
+		if err != nil {
+			return err
+		}
+
+		if baseEventMessage.Event == "applicationDidLaunch" {
+			if h, ok := p.handler.(ApplicationDidLaunchHandler); ok {
+				p.handle(func() error { return h.HandleApplicationDidLaunchEvent(p, applicationEventMessage) })
 			}
 		} else {
-			log.Printf("Not handled: %v", baseEventMessage.Event)
+			if h, ok := p.handler.(ApplicationDidTerminateHandler); ok {
+				p.handle(func() error { return h.HandleApplicationDidTerminateEvent(p, applicationEventMessage) })
+			}
+		}
+	} else if baseEventMessage.Event == "didReceiveGlobalSettings" {
+		var didReceiveGlobalSettingsEventMessage DidReceiveGlobalSettingsEventMessage
+		err = json.Unmarshal(data, &didReceiveGlobalSettingsEventMessage)
+		if err != nil {
+			return err
+		}
+
+		if h, ok := p.handler.(DidReceiveGlobalSettingsHandler); ok {
+			p.handle(func() error { return h.HandleDidReceiveGlobalSettingsEvent(p, didReceiveGlobalSettingsEventMessage) })
+		}
+	} else if baseEventMessage.Event == "systemDidWakeUp" {
+		var systemDidWakeUpEventMessage SystemDidWakeUpEventMessage
+		err = json.Unmarshal(data, &systemDidWakeUpEventMessage)
+		if err != nil {
+			return err
+		}
+
+		if h, ok := p.handler.(SystemDidWakeUpHandler); ok {
+			p.handle(func() error { return h.HandleSystemDidWakeUpEvent(p, systemDidWakeUpEventMessage) })
 		}
+	} else {
+		log.Printf("Not handled: %v", baseEventMessage.Event)
 	}
 	return nil
 }
@@ -0,0 +1,99 @@
+package sdplugin
+
+import (
+	"testing"
+	"time"
+)
+
+// defaultHandler handles key down and key up of all actions
+type defaultHandler struct {
+	calls chan string
+}
+
+func (h defaultHandler) HandleKeyDownEvent(sender Sender, event KeyEventMessage) error {
+	h.calls <- "default keyDown " + event.Action
+	return nil
+}
+
+func (h defaultHandler) HandleKeyUpEvent(sender Sender, event KeyEventMessage) error {
+	h.calls <- "default keyUp " + event.Action
+	return nil
+}
+
+// actionHandler handles only key down of its action
+type actionHandler struct {
+	calls chan string
+}
+
+func (h actionHandler) HandleKeyDownEvent(sender Sender, event KeyEventMessage) error {
+	h.calls <- "action keyDown " + event.Action
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "registered handler wins",
+			data: `{"event":"keyDown","action":"registered","context":"1"}`,
+			want: "action keyDown registered",
+		},
+		{
+			name: "unimplemented event falls back to default",
+			data: `{"event":"keyUp","action":"registered","context":"1"}`,
+			want: "default keyUp registered",
+		},
+		{
+			name: "unregistered action goes to default",
+			data: `{"event":"keyDown","action":"unregistered","context":"2"}`,
+			want: "default keyDown unregistered",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := make(chan string, 2)
+			p := &Plugin{
+				handler:        defaultHandler{calls: calls},
+				actionHandlers: make(map[string]Handler),
+			}
+			p.Register("registered", actionHandler{calls: calls})
+
+			err := p.dispatch([]byte(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case got := <-calls:
+				if got != test.want {
+					t.Errorf("handled by %q, want %q", got, test.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("event was not handled, want %q", test.want)
+			}
+
+			// handlers run on their own goroutine, give a second handler the chance to show up
+			select {
+			case got := <-calls:
+				t.Errorf("event handled twice, also by %q", got)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestDispatchInvalidMessage(t *testing.T) {
+	p := &Plugin{
+		handler:        defaultHandler{calls: make(chan string, 1)},
+		actionHandlers: make(map[string]Handler),
+	}
+
+	err := p.dispatch([]byte(`not json`))
+	if err == nil {
+		t.Error("dispatch() error = nil, want error")
+	}
+}
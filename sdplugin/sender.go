@@ -10,9 +10,15 @@ type Sender interface {
 	ShowAlert(context string) error
 	ShowOk(context string) error
 	SetSettings(context string, payload interface{}) error
+	GetSettings(context string) error
+	GetGlobalSettings() error
+	SetGlobalSettings(payload interface{}) error
 	SendToPropertyInspector(context string, action string, payload interface{}) error
 	SetTitle(context string, title string, target string) error
 	SetImage(context string, image string, target string) error
+	SetFeedback(context string, payload interface{}) error
+	SetFeedbackLayout(context string, layout string) error
+	SetTriggerDescription(context string, description TriggerDescription) error
 	SwitchToProfile(context string, device string, profile string) error
 	OpenURL(url string) error
 }
@@ -64,6 +70,38 @@ func (p *Plugin) SetSettings(context string, payload interface{}) error {
 	})
 }
 
+// GetSettings requests the settings of an action.
+// They are received by DidReceiveSettingsHandler.
+func (p *Plugin) GetSettings(context string) error {
+	return p.sendMessage(&GetSettingsEventMessage{
+		Event:   "getSettings",
+		Context: context,
+	})
+}
+
+// GetGlobalSettings requests the settings shared by all actions of the plugin.
+// They are received by DidReceiveGlobalSettingsHandler.
+func (p *Plugin) GetGlobalSettings() error {
+	return p.sendMessage(&GetSettingsEventMessage{
+		Event:   "getGlobalSettings",
+		Context: pluginUUID,
+	})
+}
+
+// SetGlobalSettings shared by all actions of the plugin
+func (p *Plugin) SetGlobalSettings(payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return p.sendMessage(&SetSettingsEventMessage{
+		Event:   "setGlobalSettings",
+		Context: pluginUUID,
+		Payload: data,
+	})
+}
+
 // SendToPropertyInspector send data to the PropertyInspector
 func (p *Plugin) SendToPropertyInspector(context string, action string, payload interface{}) error {
 	data, err := json.Marshal(payload)
@@ -105,6 +143,42 @@ func (p *Plugin) SetImage(context string, image string, target string) error {
 	})
 }
 
+// SetFeedback changes the touch strip of a Stream Deck+ encoder.
+// The payload depends on the layout, e.g. BarFeedback for LayoutBar.
+func (p *Plugin) SetFeedback(context string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return p.sendMessage(&SetFeedbackEventMessage{
+		Event:   "setFeedback",
+		Context: context,
+		Payload: data,
+	})
+}
+
+// SetFeedbackLayout changes the touch strip layout of a Stream Deck+ encoder.
+// See Layout constants for the built-in layouts.
+func (p *Plugin) SetFeedbackLayout(context string, layout string) error {
+	return p.sendMessage(&SetFeedbackLayoutEventMessage{
+		Event:   "setFeedbackLayout",
+		Context: context,
+		Payload: SetFeedbackLayoutPayload{
+			Layout: layout,
+		},
+	})
+}
+
+// SetTriggerDescription changes the descriptions of the encoder interactions shown in the app
+func (p *Plugin) SetTriggerDescription(context string, description TriggerDescription) error {
+	return p.sendMessage(&SetTriggerDescriptionEventMessage{
+		Event:   "setTriggerDescription",
+		Context: context,
+		Payload: description,
+	})
+}
+
 //SwitchToProfile with the given profile name.
 func (p *Plugin) SwitchToProfile(context string, device string, profile string) error {
 	return p.sendMessage(&SwitchToProfileEventMessage{
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// sleepTimes the sleep action cycles through in minutes
+var sleepTimes = []int{0, 30, 60, 90, 120}
+
+// longPressDuration after which a press of the sleep action clears the timer
+const longPressDuration = 500 * time.Millisecond
+
+// sleepTickInterval in which the remaining minutes of running sleep timers are updated
+const sleepTickInterval = 15 * time.Second
+
+// sleepTimer is a running sleep timer of a device zone.
+// The device only reports the minutes the timer was set to, so the expiry is tracked by the plugin.
+type sleepTimer struct {
+	minutes int
+	expiry  time.Time
+}
+
+// sleepKey sets the sleep timer on release, a long press clears it
+type sleepKey struct {
+	handler *musicCastHandler
+}
+
+func (a sleepKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	return a.keyDown(event.Context)
+}
+
+func (a sleepKey) HandleKeyUpEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyUp(sender, event.Context, settings)
+}
+
+func (a sleepKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	return a.show(sender, context, actionContext.settings.deviceKey(), state.status)
+}
+
+// keyDown remembers when the key was pressed, the timer is set when it is released
+func (a sleepKey) keyDown(sdContext string) error {
+	a.handler.keyDownMapMutex.Lock()
+	a.handler.keyDownMap[sdContext] = time.Now()
+	a.handler.keyDownMapMutex.Unlock()
+	return nil
+}
+
+// keyUp sets the sleep timer to the next time. A long press clears the timer.
+func (a sleepKey) keyUp(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	a.handler.keyDownMapMutex.Lock()
+	keyDown, ok := a.handler.keyDownMap[sdContext]
+	delete(a.handler.keyDownMap, sdContext)
+	a.handler.keyDownMapMutex.Unlock()
+
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	minutes := nextSleepTime(status.Sleep)
+	if ok && time.Since(keyDown) >= longPressDuration {
+		minutes = 0
+	}
+
+	err = device.SetSleep(context.Background(), minutes)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+	a.handler.startSleepTimer(settings.deviceKey(), minutes)
+
+	status, err = device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return a.show(sender, sdContext, settings.deviceKey(), status)
+}
+
+// nextSleepTime returns the sleep time following current, 0 after the longest time
+func nextSleepTime(current int) int {
+	for _, minutes := range sleepTimes {
+		if minutes > current {
+			return minutes
+		}
+	}
+	return 0
+}
+
+// show renders the remaining minutes of the sleep timer as title, nothing if it is off
+func (a sleepKey) show(sender sdplugin.Sender, sdContext string, key deviceKey, status *musiccast.Status) error {
+	title := ""
+	if remaining := a.handler.sleepRemaining(key, status.Sleep); remaining > 0 {
+		// round up, the timer shows 1 min until the device turns off
+		minutes := int((remaining + time.Minute - 1) / time.Minute)
+		title = fmt.Sprintf("%v min", minutes)
+	}
+	return sender.SetTitle(sdContext, title, sdplugin.TargetBoth)
+}
+
+// startSleepTimer remembers when the sleep timer of the device zone, which was just set, expires
+func (m *musicCastHandler) startSleepTimer(key deviceKey, minutes int) {
+	m.sleepMapMutex.Lock()
+	defer m.sleepMapMutex.Unlock()
+
+	if minutes == 0 {
+		delete(m.sleepMap, key)
+		return
+	}
+	m.sleepMap[key] = sleepTimer{minutes: minutes, expiry: time.Now().Add(time.Duration(minutes) * time.Minute)}
+}
+
+// sleepRemaining returns the remaining time of the sleep timer the device zone reports.
+// Timers set by other apps start counting down when they are seen first.
+func (m *musicCastHandler) sleepRemaining(key deviceKey, minutes int) time.Duration {
+	m.sleepMapMutex.Lock()
+	defer m.sleepMapMutex.Unlock()
+
+	if minutes == 0 {
+		delete(m.sleepMap, key)
+		return 0
+	}
+
+	timer, ok := m.sleepMap[key]
+	if !ok || timer.minutes != minutes {
+		timer = sleepTimer{minutes: minutes, expiry: time.Now().Add(time.Duration(minutes) * time.Minute)}
+		m.sleepMap[key] = timer
+	}
+
+	// the device turns off a bit later than expected, keep showing the last minute
+	remaining := time.Until(timer.expiry)
+	if remaining < time.Minute {
+		remaining = time.Minute
+	}
+	return remaining
+}
+
+// sleepWorker counts down the titles of all sleep keys with a running timer
+func (m *musicCastHandler) sleepWorker(sender sdplugin.Sender) {
+	for range time.Tick(sleepTickInterval) {
+		m.contextMapMutex.Lock()
+		sleepContexts := make(map[string]actionContext)
+		for context, actionContext := range m.contextMap {
+			if actionContext.action == sleepAction {
+				sleepContexts[context] = actionContext
+			}
+		}
+		m.contextMapMutex.Unlock()
+
+		for context, actionContext := range sleepContexts {
+			key := actionContext.settings.deviceKey()
+			m.pollerMapMutex.Lock()
+			var state deviceState
+			if poller, ok := m.pollerMap[key]; ok {
+				state = poller.state
+			}
+			m.pollerMapMutex.Unlock()
+			if state.status == nil || state.status.Sleep == 0 {
+				continue
+			}
+
+			err := m.renderContext(sender, context, actionContext, state)
+			if err != nil {
+				log.Printf("Failed to update sleep timer: %v\n", err)
+			}
+		}
+	}
+}
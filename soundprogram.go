@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// soundProgramModeCycle steps through Settings.SoundPrograms instead of selecting Settings.SoundProgram
+const soundProgramModeCycle = "cycle"
+
+// surroundDecoderProgram is the sound program playing with the surround decoder of Status.SurrDecoderType
+const surroundDecoderProgram = "surr_decoder"
+
+// soundProgramKey selects a sound program and surround decoder or cycles through several programs
+type soundProgramKey struct {
+	handler *musicCastHandler
+}
+
+func (a soundProgramKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a soundProgramKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	err := sender.SetState(context, soundProgramState(state.status, actionContext.settings))
+	if err != nil {
+		return err
+	}
+	return showSoundProgram(sender, context, state.status, actionContext.settings)
+}
+
+// keyDown selects the configured sound program or the next one of the cycle
+func (a soundProgramKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	program := settings.SoundProgram
+	if settings.SoundProgramMode == soundProgramModeCycle {
+		program = nextSoundProgram(settings.SoundPrograms, status.SoundProgram)
+	}
+	if program == "" {
+		sender.ShowAlert(sdContext)
+		return errors.New("No sound program configured")
+	}
+
+	err = device.SetSoundProgram(context.Background(), program)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	if program == surroundDecoderProgram && settings.SurroundDecoder != "" {
+		err = device.SetSurroundDecoderType(context.Background(), settings.SurroundDecoder)
+		if err != nil {
+			sender.ShowAlert(sdContext)
+			return err
+		}
+	}
+
+	status, err = device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	setStateDelayed(sender, sdContext, soundProgramState(status, settings))
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return showSoundProgram(sender, sdContext, status, settings)
+}
+
+// nextSoundProgram returns the program following current in programs.
+// The first program is returned if current is not part of programs.
+func nextSoundProgram(programs []string, current string) string {
+	if len(programs) == 0 {
+		return ""
+	}
+	for i, program := range programs {
+		if program == current {
+			return programs[(i+1)%len(programs)]
+		}
+	}
+	return programs[0]
+}
+
+// showSoundProgram shows the current sound program as title when cycling,
+// the surround decoder for the surround decoder program.
+// A key selecting a single program keeps its title.
+func showSoundProgram(sender sdplugin.Sender, sdContext string, status *musiccast.Status, settings Settings) error {
+	if settings.SoundProgramMode != soundProgramModeCycle {
+		return nil
+	}
+	title := displayName(status.SoundProgram)
+	if status.SoundProgram == surroundDecoderProgram && status.SurrDecoderType != "" {
+		title = displayName(status.SurrDecoderType)
+	}
+	return sender.SetTitle(sdContext, title, sdplugin.TargetBoth)
+}
+
+// displayName returns a readable name of an id of the device, e.g. "7ch Stereo" for "7ch_stereo"
+func displayName(id string) string {
+	words := strings.Split(id, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// soundProgramState returns the streamdeck state for the current sound program of the device
+func soundProgramState(status *musiccast.Status, settings Settings) int {
+	active := status.SoundProgram == settings.SoundProgram
+	if settings.SoundProgramMode == soundProgramModeCycle {
+		active = musiccast.Contains(settings.SoundPrograms, status.SoundProgram)
+	}
+	if status.SoundProgram == surroundDecoderProgram && settings.SurroundDecoder != "" {
+		active = active && status.SurrDecoderType == settings.SurroundDecoder
+	}
+	if active {
+		return 0 // active
+	}
+	return 1 // not active
+}
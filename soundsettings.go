@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// soundFunction is a sound setting of a zone which can be switched on and off
+type soundFunction struct {
+	enabled func(status *musiccast.Status) bool
+	set     func(device *musiccast.Client, enable bool) error
+}
+
+// soundFunctions by their id in the func_list of the zone features
+var soundFunctions = map[string]soundFunction{
+	"enhancer": {
+		enabled: func(status *musiccast.Status) bool { return status.Enhancer },
+		set: func(device *musiccast.Client, enable bool) error {
+			return device.SetEnhancer(context.Background(), enable)
+		},
+	},
+	"pure_direct": {
+		enabled: func(status *musiccast.Status) bool { return status.PureDirect },
+		set: func(device *musiccast.Client, enable bool) error {
+			return device.SetPureDirect(context.Background(), enable)
+		},
+	},
+	"extra_bass": {
+		enabled: func(status *musiccast.Status) bool { return status.ExtraBass },
+		set: func(device *musiccast.Client, enable bool) error {
+			return device.SetExtraBass(context.Background(), enable)
+		},
+	},
+	"clear_voice": {
+		enabled: func(status *musiccast.Status) bool { return status.ClearVoice },
+		set: func(device *musiccast.Client, enable bool) error {
+			return device.SetClearVoice(context.Background(), enable)
+		},
+	},
+	"direct": {
+		enabled: func(status *musiccast.Status) bool { return status.Direct },
+		set: func(device *musiccast.Client, enable bool) error {
+			return device.SetDirect(context.Background(), enable)
+		},
+	},
+}
+
+// soundFunctionIDs in the order they are offered in the property inspector
+var soundFunctionIDs = []string{"pure_direct", "direct", "enhancer", "extra_bass", "clear_voice"}
+
+// toneControlFunc is the id of tone control in the func_list and range_step of the zone features
+const toneControlFunc = "tone_control"
+
+// toneControlModeManual is needed for bass and treble to take effect
+const toneControlModeManual = "manual"
+
+// toneControls which can be changed by the tone control action
+var toneControls = []string{"bass", "treble"}
+
+// soundToggleKey switches a sound function on or off
+type soundToggleKey struct {
+	handler *musicCastHandler
+}
+
+func (a soundToggleKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a soundToggleKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	return sender.SetState(context, soundToggleState(state.status, actionContext.settings.Function))
+}
+
+// keyDown switches the configured sound function on or off
+func (a soundToggleKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	function, ok := soundFunctions[settings.Function]
+	if !ok {
+		sender.ShowAlert(sdContext)
+		return fmt.Errorf("Unknown sound function %v", settings.Function)
+	}
+
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	err = function.set(device, !function.enabled(status))
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err = device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	setStateDelayed(sender, sdContext, soundToggleState(status, settings.Function))
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return nil
+}
+
+// soundToggleState returns the streamdeck state for the sound function of the zone
+func soundToggleState(status *musiccast.Status, function string) int {
+	if f, ok := soundFunctions[function]; ok && f.enabled(status) {
+		return 0 // on
+	}
+	return 1 // off
+}
+
+// toneControlKey raises or lowers bass or treble
+type toneControlKey struct {
+	handler *musicCastHandler
+}
+
+func (a toneControlKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a toneControlKey) render(sender sdplugin.Sender, context string, actionContext actionContext, state deviceState) error {
+	return showToneControl(sender, context, state.status, actionContext.settings.Tone)
+}
+
+// keyDown raises or lowers bass or treble by one step of the device
+func (a toneControlKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	features, err := a.handler.features(settings.deviceKey().ip)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+	zone := features.ZoneFeatures(settings.deviceKey().zone)
+	if zone == nil || !zone.HasFunc(toneControlFunc) {
+		sender.ShowAlert(sdContext)
+		return fmt.Errorf("Zone %v does not support tone control", settings.deviceKey().zone)
+	}
+	// bass and treble share the range of tone control
+	rangeStep := zone.RangeStepFor(toneControlFunc)
+	if rangeStep == nil {
+		sender.ShowAlert(sdContext)
+		return fmt.Errorf("Zone %v has no tone control range", settings.deviceKey().zone)
+	}
+
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	toneControl := status.ToneControl
+	value := &toneControl.Bass
+	if settings.Tone == "treble" {
+		value = &toneControl.Treble
+	}
+	step := int(rangeStep.Step)
+	if settings.Direction == musiccast.DirectionDown {
+		step = -step
+	}
+	*value = clamp(*value+step, int(rangeStep.Min), int(rangeStep.Max))
+
+	if musiccast.Contains(zone.ToneControlModeList, toneControlModeManual) {
+		toneControl.Mode = toneControlModeManual
+	}
+	err = device.SetToneControl(context.Background(), toneControl)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err = device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return showToneControl(sender, sdContext, status, settings.Tone)
+}
+
+// showToneControl renders the current bass or treble as title
+func showToneControl(sender sdplugin.Sender, sdContext string, status *musiccast.Status, tone string) error {
+	value := status.ToneControl.Bass
+	if tone == "treble" {
+		value = status.ToneControl.Treble
+	}
+	return sender.SetTitle(sdContext, strconv.Itoa(value), sdplugin.TargetBoth)
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
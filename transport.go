@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// cdInput is the id of the CD player input
+const cdInput = "cd"
+
+// transportCommands maps transport actions to their playback command
+var transportCommands = map[string]string{
+	stopAction:     musiccast.PlaybackStop,
+	nextAction:     musiccast.PlaybackNext,
+	previousAction: musiccast.PlaybackPrevious,
+}
+
+// transportKey sends the playback command of its action
+type transportKey struct {
+	handler *musicCastHandler
+	action  string
+}
+
+func (a transportKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a transportKey) render(sender sdplugin.Sender, context string, _ actionContext, state deviceState) error {
+	if a.action != playPauseAction {
+		return nil
+	}
+	if playback, ok := state.playback(); ok {
+		return sender.SetState(context, playPauseState(playback))
+	}
+	return nil
+}
+
+// keyDown sends the playback command of the action to net/usb or the CD player,
+// depending on the current input
+func (a transportKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	features, err := a.handler.features(settings.deviceKey().ip)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	playInfoType := features.System.PlayInfoType(status.Input)
+	if playInfoType != musiccast.PlayInfoTypeNetUSB && playInfoType != musiccast.PlayInfoTypeCD {
+		sender.ShowAlert(sdContext)
+		return fmt.Errorf("Input %v does not support transport control", status.Input)
+	}
+
+	command, ok := transportCommands[a.action]
+	if !ok {
+		// play/pause depends on the current playback
+		command, err = a.playPauseCommand(device, playInfoType)
+		if err != nil {
+			sender.ShowAlert(sdContext)
+			return err
+		}
+	}
+
+	if playInfoType == musiccast.PlayInfoTypeCD {
+		err = device.SetCDPlayback(context.Background(), command)
+	} else {
+		err = device.SetNetUSBPlayback(context.Background(), command)
+	}
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	if a.action == playPauseAction {
+		setStateDelayed(sender, sdContext, playPauseState(command))
+	}
+	a.handler.refreshDevice(settings.deviceKey())
+	return nil
+}
+
+// playPauseCommand returns pause if the source is playing and play otherwise
+func (a transportKey) playPauseCommand(device *musiccast.Client, playInfoType string) (string, error) {
+	var playback string
+	if playInfoType == musiccast.PlayInfoTypeCD {
+		playInfo, err := device.GetCDPlayInfo(context.Background())
+		if err != nil {
+			return "", err
+		}
+		playback = playInfo.Playback
+	} else {
+		playInfo, err := device.GetNetUSBPlayInfo(context.Background())
+		if err != nil {
+			return "", err
+		}
+		playback = playInfo.Playback
+	}
+
+	if playback == musiccast.PlaybackPlay {
+		return musiccast.PlaybackPause, nil
+	}
+	return musiccast.PlaybackPlay, nil
+}
+
+// playPauseState returns the streamdeck state for the playback of the current input
+func playPauseState(playback string) int {
+	if playback == musiccast.PlaybackPlay {
+		return 1 // playing, show pause
+	}
+	return 0 // not playing, show play
+}
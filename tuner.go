@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// tunerBands the tuner actions can be configured with, if the device supports them
+var tunerBands = []string{musiccast.BandFM, musiccast.BandAM, musiccast.BandDAB}
+
+// tunerKey switches the band, tunes or recalls a preset depending on its action
+type tunerKey struct {
+	handler *musicCastHandler
+	action  string
+}
+
+func (a tunerKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a tunerKey) render(sender sdplugin.Sender, context string, _ actionContext, state deviceState) error {
+	if state.tunerPlayInfo == nil {
+		return nil
+	}
+	return showTunerStation(sender, context, state.tunerPlayInfo)
+}
+
+// keyDown switches the band, tunes or recalls a preset depending on the action
+func (a tunerKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	device := a.handler.device(settings)
+	playInfo, err := device.GetTunerPlayInfo(context.Background())
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	features, err := a.handler.features(settings.deviceKey().ip)
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	band := settings.Band
+	if band == "" {
+		band = playInfo.Band
+	}
+	if !features.Tuner.HasFunc(band) {
+		sender.ShowAlert(sdContext)
+		return fmt.Errorf("Tuner does not support band %v", band)
+	}
+
+	switch a.action {
+	case tunerBandAction:
+		err = device.SetTunerBand(context.Background(), band)
+	case tunerFreqAction:
+		err = tune(device, &features.Tuner, band, settings)
+	case tunerPresetAction:
+		if settings.Preset == 0 {
+			err = errors.New("No preset configured")
+		} else if settings.Preset > features.Tuner.Preset.Num {
+			err = fmt.Errorf("Tuner has only %v presets", features.Tuner.Preset.Num)
+		} else {
+			if features.Tuner.Preset.Type == musiccast.BandCommon {
+				band = musiccast.BandCommon
+			}
+			err = device.RecallTunerPreset(context.Background(), band, settings.Preset)
+		}
+	}
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	playInfo, err = device.GetTunerPlayInfo(context.Background())
+	if err != nil {
+		return err
+	}
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{tunerPlayInfo: playInfo}, sdContext)
+	return showTunerStation(sender, sdContext, playInfo)
+}
+
+// tune changes the frequency of band as configured in settings.
+// DAB has no frequencies, auto tuning selects the next or previous service instead.
+func tune(device *musiccast.Client, tuner *musiccast.TunerFeatures, band string, settings Settings) error {
+	tuning := settings.Tuning
+	if tuning == "" {
+		tuning = musiccast.TuningAutoUp
+	}
+
+	if band == musiccast.BandDAB {
+		switch tuning {
+		case musiccast.TuningAutoUp:
+			return device.SetDABService(context.Background(), musiccast.DirectionNext)
+		case musiccast.TuningAutoDown:
+			return device.SetDABService(context.Background(), musiccast.DirectionPrevious)
+		}
+		return errors.New("DAB can not be tuned to a frequency")
+	}
+
+	if tuning == musiccast.TuningDirect {
+		rangeStep := tuner.RangeStepFor(band)
+		if rangeStep != nil && (float64(settings.Frequency) < rangeStep.Min || float64(settings.Frequency) > rangeStep.Max) {
+			return fmt.Errorf("Frequency %v kHz out of range %v-%v kHz", settings.Frequency, rangeStep.Min, rangeStep.Max)
+		}
+	}
+	return device.SetTunerFreq(context.Background(), band, tuning, settings.Frequency)
+}
+
+// showTunerStation shows the frequency or DAB service of the current band as title
+func showTunerStation(sender sdplugin.Sender, sdContext string, playInfo *musiccast.TunerPlayInfo) error {
+	return sender.SetTitle(sdContext, tunerStation(playInfo), sdplugin.TargetBoth)
+}
+
+// tunerStation returns the frequency or DAB service of the current band
+func tunerStation(playInfo *musiccast.TunerPlayInfo) string {
+	switch playInfo.Band {
+	case musiccast.BandFM:
+		return fmt.Sprintf("%.2f MHz", float64(playInfo.FM.Freq)/1000)
+	case musiccast.BandAM:
+		return fmt.Sprintf("%v kHz", playInfo.AM.Freq)
+	case musiccast.BandDAB:
+		return playInfo.DAB.ServiceLabel
+	}
+	return ""
+}
+
+// needsTunerPlayInfo reports if the action shows tuner play info
+func needsTunerPlayInfo(action string) bool {
+	switch action {
+	case tunerBandAction, tunerFreqAction, tunerPresetAction:
+		return true
+	}
+	return false
+}
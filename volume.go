@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/LouisChrist/streamdeck-musiccast/musiccast"
+	"github.com/LouisChrist/streamdeck-musiccast/sdplugin"
+)
+
+// volumeKey raises, lowers or sets the volume depending on its action
+type volumeKey struct {
+	handler *musicCastHandler
+	action  string
+}
+
+func (a volumeKey) HandleKeyDownEvent(sender sdplugin.Sender, event sdplugin.KeyEventMessage) error {
+	settings, err := a.handler.resolveSettings(event.Payload.Settings)
+	if err != nil {
+		return err
+	}
+	return a.keyDown(sender, event.Context, settings)
+}
+
+func (a volumeKey) render(sender sdplugin.Sender, context string, _ actionContext, state deviceState) error {
+	return showVolume(sender, context, state.status)
+}
+
+// keyDown changes the volume depending on the action and shows the new volume as title
+func (a volumeKey) keyDown(sender sdplugin.Sender, sdContext string, settings Settings) error {
+	if a.action == volumeSetAction && settings.Volume == nil {
+		sender.ShowAlert(sdContext)
+		return errors.New("No volume configured")
+	}
+
+	device := a.handler.device(settings)
+
+	var err error
+	switch a.action {
+	case volumeUpAction:
+		err = device.SetVolumeStep(context.Background(), musiccast.DirectionUp, settings.Step)
+	case volumeDownAction:
+		err = device.SetVolumeStep(context.Background(), musiccast.DirectionDown, settings.Step)
+	case volumeSetAction:
+		err = device.SetVolume(context.Background(), *settings.Volume)
+	}
+	if err != nil {
+		sender.ShowAlert(sdContext)
+		return err
+	}
+
+	status, err := device.GetStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	a.handler.deviceChanged(sender, settings.deviceKey(), deviceState{status: status}, sdContext)
+	return showVolume(sender, sdContext, status)
+}
+
+// showVolume renders the current volume as title
+func showVolume(sender sdplugin.Sender, sdContext string, status *musiccast.Status) error {
+	return sender.SetTitle(sdContext, strconv.Itoa(status.Volume), sdplugin.TargetBoth)
+}